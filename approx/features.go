@@ -0,0 +1,115 @@
+package approx
+
+import "math"
+
+// Features maps a gridworld block to a fixed length feature vector.
+type Features interface {
+	Dim() int
+	Features(state [2]int) []float64
+}
+
+type OneHot struct {
+	width  int
+	height int
+}
+
+func NewOneHot(width, height int) *OneHot {
+	return &OneHot{width: width, height: height}
+}
+
+func (o *OneHot) Dim() int {
+	return o.width * o.height
+}
+
+func (o *OneHot) Features(state [2]int) []float64 {
+	x := make([]float64, o.Dim())
+	x[state[1]*o.width+state[0]] = 1.0
+	return x
+}
+
+func normalize(value, size int) float64 {
+	if size <= 1 {
+		return 0.0
+	}
+	return float64(value) / float64(size-1)
+}
+
+// Coordinates is a bias followed by the block position scaled to [0, 1].
+type Coordinates struct {
+	width  int
+	height int
+}
+
+func NewCoordinates(width, height int) *Coordinates {
+	return &Coordinates{width: width, height: height}
+}
+
+func (c *Coordinates) Dim() int {
+	return 3
+}
+
+func (c *Coordinates) Features(state [2]int) []float64 {
+	return []float64{1.0, normalize(state[0], c.width), normalize(state[1], c.height)}
+}
+
+// Polynomial holds every x^i * y^j with i + j <= degree on the scaled
+// coordinates, the constant term included.
+type Polynomial struct {
+	width  int
+	height int
+	degree int
+}
+
+func NewPolynomial(width, height, degree int) *Polynomial {
+	return &Polynomial{width: width, height: height, degree: degree}
+}
+
+func (p *Polynomial) Dim() int {
+	return (p.degree + 1) * (p.degree + 2) / 2
+}
+
+func (p *Polynomial) Features(state [2]int) []float64 {
+	x := normalize(state[0], p.width)
+	y := normalize(state[1], p.height)
+	features := make([]float64, 0, p.Dim())
+	for total := 0; total <= p.degree; total++ {
+		for i := total; i >= 0; i-- {
+			features = append(features, math.Pow(x, float64(i))*math.Pow(y, float64(total-i)))
+		}
+	}
+	return features
+}
+
+// RadialBasis places gaussian bumps of width sigma on a centersX by centersY
+// grid spread evenly over the scaled coordinates.
+type RadialBasis struct {
+	width   int
+	height  int
+	centers [][2]float64
+	sigma   float64
+}
+
+func NewRadialBasis(width, height, centersX, centersY int, sigma float64) *RadialBasis {
+	centers := make([][2]float64, 0, centersX*centersY)
+	for j := 0; j < centersY; j++ {
+		for i := 0; i < centersX; i++ {
+			centers = append(centers, [2]float64{normalize(i, centersX), normalize(j, centersY)})
+		}
+	}
+	return &RadialBasis{width: width, height: height, centers: centers, sigma: sigma}
+}
+
+func (r *RadialBasis) Dim() int {
+	return len(r.centers)
+}
+
+func (r *RadialBasis) Features(state [2]int) []float64 {
+	x := normalize(state[0], r.width)
+	y := normalize(state[1], r.height)
+	features := make([]float64, len(r.centers))
+	for i, center := range r.centers {
+		distance := (x-center[0])*(x-center[0]) + (y-center[1])*(y-center[1])
+		features[i] = math.Exp(-distance / (2 * r.sigma * r.sigma))
+	}
+	return features
+}
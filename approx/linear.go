@@ -0,0 +1,75 @@
+package approx
+
+func dot(w, x []float64) float64 {
+	sum := 0.0
+	for i := range w {
+		sum += w[i] * x[i]
+	}
+	return sum
+}
+
+// LinearV approximates v(s) as w . x(s).
+type LinearV struct {
+	features Features
+	w        []float64
+}
+
+func NewLinearV(features Features) *LinearV {
+	return &LinearV{features: features, w: make([]float64, features.Dim())}
+}
+
+func (l *LinearV) Value(state [2]int) float64 {
+	return dot(l.w, l.features.Features(state))
+}
+
+// Update moves the weights by alpha * delta along the gradient x(s), which is
+// the semi-gradient step when delta is a bootstrapped TD error.
+func (l *LinearV) Update(state [2]int, alpha float64, delta float64) {
+	x := l.features.Features(state)
+	for i := range l.w {
+		l.w[i] += alpha * delta * x[i]
+	}
+}
+
+func (l *LinearV) Weights() []float64 {
+	return l.w
+}
+
+// LinearQ keeps one weight vector per action so that q(s, a) = w_a . x(s).
+type LinearQ struct {
+	features Features
+	w        map[int][]float64
+}
+
+func NewLinearQ(features Features, actions []int) *LinearQ {
+	w := make(map[int][]float64)
+	for _, action := range actions {
+		w[action] = make([]float64, features.Dim())
+	}
+	return &LinearQ{features: features, w: w}
+}
+
+func (l *LinearQ) Value(state [2]int, action int) float64 {
+	return dot(l.w[action], l.features.Features(state))
+}
+
+func (l *LinearQ) StateValues(state [2]int) map[int]float64 {
+	x := l.features.Features(state)
+	values := make(map[int]float64)
+	for action, w := range l.w {
+		values[action] = dot(w, x)
+	}
+	return values
+}
+
+func (l *LinearQ) Update(state [2]int, action int, alpha float64, delta float64) {
+	x := l.features.Features(state)
+	w := l.w[action]
+	for i := range w {
+		w[i] += alpha * delta * x[i]
+	}
+}
+
+func (l *LinearQ) Weights() map[int][]float64 {
+	return l.w
+}
@@ -0,0 +1,64 @@
+package approx
+
+import "math"
+
+// SoftmaxPolicy chooses actions with probability proportional to
+// exp(theta_a . x(s)). With OneHot features the preferences are a plain
+// table of logits.
+type SoftmaxPolicy struct {
+	features Features
+	theta    map[int][]float64
+	actions  []int
+}
+
+func NewSoftmaxPolicy(features Features, actions []int) *SoftmaxPolicy {
+	theta := make(map[int][]float64)
+	for _, action := range actions {
+		theta[action] = make([]float64, features.Dim())
+	}
+	return &SoftmaxPolicy{features: features, theta: theta, actions: actions}
+}
+
+func (p *SoftmaxPolicy) probs(x []float64) map[int]float64 {
+	preferences := make(map[int]float64)
+	maxPreference := math.Inf(-1)
+	for _, action := range p.actions {
+		preferences[action] = dot(p.theta[action], x)
+		maxPreference = math.Max(maxPreference, preferences[action])
+	}
+	sum := 0.0
+	for action, preference := range preferences {
+		preferences[action] = math.Exp(preference - maxPreference)
+		sum += preferences[action]
+	}
+	for action := range preferences {
+		preferences[action] /= sum
+	}
+	return preferences
+}
+
+func (p *SoftmaxPolicy) Probs(state [2]int) map[int]float64 {
+	return p.probs(p.features.Features(state))
+}
+
+// Update moves theta by alpha * scale along grad ln pi(action|state), which
+// for a softmax over linear preferences is x(s) (1[a = b] - pi(b|s)) for
+// every action b.
+func (p *SoftmaxPolicy) Update(state [2]int, action int, alpha float64, scale float64) {
+	x := p.features.Features(state)
+	probs := p.probs(x)
+	for _, b := range p.actions {
+		indicator := 0.0
+		if b == action {
+			indicator = 1.0
+		}
+		theta := p.theta[b]
+		for i := range theta {
+			theta[i] += alpha * scale * (indicator - probs[b]) * x[i]
+		}
+	}
+}
+
+func (p *SoftmaxPolicy) Weights() map[int][]float64 {
+	return p.theta
+}
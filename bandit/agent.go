@@ -0,0 +1,201 @@
+package bandit
+
+import (
+	"math"
+	"math/rand"
+
+	"reinforcement-learning-playground/explorer"
+	"reinforcement-learning-playground/schedule"
+)
+
+type Agent interface {
+	SelectArm() int
+	Update(arm int, reward float64)
+}
+
+// A bandit has a single state.
+var banditState = explorer.NoState{}
+
+func sampleArm(probs map[int]float64, arms int, rng *rand.Rand) int {
+	cumProb := 0.0
+	sample := rng.Float64()
+	for arm := 0; arm < arms; arm++ {
+		cumProb += probs[arm]
+		if sample < cumProb {
+			return arm
+		}
+	}
+	return arms - 1
+}
+
+// ValueAgent estimates arm values with a constant step size alpha, or with
+// sample averages when alpha is 0, and picks arms through an explorer.
+type ValueAgent struct {
+	explorer explorer.Explorer[explorer.NoState]
+	alpha    float64
+	q        map[int]float64
+	counts   []int
+	rng      *rand.Rand
+}
+
+func NewValueAgent(arms int, explorer explorer.Explorer[explorer.NoState], alpha float64, rng *rand.Rand) *ValueAgent {
+	q := make(map[int]float64)
+	for arm := 0; arm < arms; arm++ {
+		q[arm] = explorer.InitialQ()
+	}
+	return &ValueAgent{
+		explorer: explorer,
+		alpha:    alpha,
+		q:        q,
+		counts:   make([]int, arms),
+		rng:      rng,
+	}
+}
+
+func NewEpsilonGreedyAgent(arms int, epsilon float64, alpha float64, rng *rand.Rand) *ValueAgent {
+	return NewValueAgent(arms, explorer.NewEpsilonGreedy[explorer.NoState](schedule.Constant(epsilon)), alpha, rng)
+}
+
+func NewUCBAgent(arms int, c float64, alpha float64, rng *rand.Rand) *ValueAgent {
+	return NewValueAgent(arms, explorer.NewUCB[explorer.NoState](c), alpha, rng)
+}
+
+func (v *ValueAgent) SelectArm() int {
+	arm := sampleArm(v.explorer.Probs(banditState, v.q), len(v.counts), v.rng)
+	v.explorer.Observe(banditState, arm)
+	v.explorer.Step()
+	return arm
+}
+
+func (v *ValueAgent) Update(arm int, reward float64) {
+	v.counts[arm]++
+	alpha := v.alpha
+	if alpha == 0 {
+		alpha = 1.0 / float64(v.counts[arm])
+	}
+	v.q[arm] += (reward - v.q[arm]) * alpha
+}
+
+// GradientAgent is the gradient bandit of section 2.8: a softmax over
+// preferences with the average reward as baseline.
+type GradientAgent struct {
+	softmax     *explorer.Boltzmann[explorer.NoState]
+	alpha       float64
+	preferences map[int]float64
+	baseline    float64
+	steps       int
+	rng         *rand.Rand
+}
+
+func NewGradientAgent(arms int, alpha float64, rng *rand.Rand) *GradientAgent {
+	preferences := make(map[int]float64)
+	for arm := 0; arm < arms; arm++ {
+		preferences[arm] = 0.0
+	}
+	return &GradientAgent{
+		softmax:     explorer.NewBoltzmann[explorer.NoState](schedule.Constant(1.0)),
+		alpha:       alpha,
+		preferences: preferences,
+		rng:         rng,
+	}
+}
+
+func (g *GradientAgent) SelectArm() int {
+	return sampleArm(g.softmax.Probs(banditState, g.preferences), len(g.preferences), g.rng)
+}
+
+// Update steps the preferences along (R_t - baseline) with the baseline
+// averaged over the earlier rewards only, and adds R_t to it afterwards.
+func (g *GradientAgent) Update(arm int, reward float64) {
+	probs := g.softmax.Probs(banditState, g.preferences)
+	advantage := reward - g.baseline
+	for a := range g.preferences {
+		if a == arm {
+			g.preferences[a] += g.alpha * advantage * (1 - probs[a])
+		} else {
+			g.preferences[a] -= g.alpha * advantage * probs[a]
+		}
+	}
+	g.steps++
+	g.baseline += (reward - g.baseline) / float64(g.steps)
+}
+
+// BetaThompsonAgent keeps a Beta(1, 1) prior per arm and suits rewards in
+// [0, 1] such as Bernoulli bandits.
+type BetaThompsonAgent struct {
+	alphas []float64
+	betas  []float64
+	rng    *rand.Rand
+}
+
+func NewBetaThompsonAgent(arms int, rng *rand.Rand) *BetaThompsonAgent {
+	alphas := make([]float64, arms)
+	betas := make([]float64, arms)
+	for arm := 0; arm < arms; arm++ {
+		alphas[arm] = 1.0
+		betas[arm] = 1.0
+	}
+	return &BetaThompsonAgent{alphas: alphas, betas: betas, rng: rng}
+}
+
+func (b *BetaThompsonAgent) SelectArm() int {
+	samples := make([]float64, len(b.alphas))
+	for arm := range samples {
+		x := sampleGamma(b.alphas[arm], b.rng)
+		y := sampleGamma(b.betas[arm], b.rng)
+		samples[arm] = x / (x + y)
+	}
+	return argmaxSlice(samples)
+}
+
+func (b *BetaThompsonAgent) Update(arm int, reward float64) {
+	b.alphas[arm] += reward
+	b.betas[arm] += 1 - reward
+}
+
+// GaussianThompsonAgent assumes unit variance rewards and a N(0, 1) prior on
+// every arm mean.
+type GaussianThompsonAgent struct {
+	sums   []float64
+	counts []int
+	rng    *rand.Rand
+}
+
+func NewGaussianThompsonAgent(arms int, rng *rand.Rand) *GaussianThompsonAgent {
+	return &GaussianThompsonAgent{sums: make([]float64, arms), counts: make([]int, arms), rng: rng}
+}
+
+func (g *GaussianThompsonAgent) SelectArm() int {
+	samples := make([]float64, len(g.sums))
+	for arm := range samples {
+		precision := float64(g.counts[arm]) + 1.0
+		samples[arm] = g.sums[arm]/precision + g.rng.NormFloat64()/math.Sqrt(precision)
+	}
+	return argmaxSlice(samples)
+}
+
+func (g *GaussianThompsonAgent) Update(arm int, reward float64) {
+	g.sums[arm] += reward
+	g.counts[arm]++
+}
+
+// sampleGamma uses Marsaglia and Tsang's method.
+func sampleGamma(shape float64, rng *rand.Rand) float64 {
+	if shape < 1 {
+		return sampleGamma(shape+1, rng) * math.Pow(rng.Float64(), 1/shape)
+	}
+	d := shape - 1.0/3.0
+	c := 1.0 / math.Sqrt(9*d)
+	for {
+		x := rng.NormFloat64()
+		v := 1 + c*x
+		if v <= 0 {
+			continue
+		}
+		v = v * v * v
+		u := rng.Float64()
+		if math.Log(u) < 0.5*x*x+d-d*v+d*math.Log(v) {
+			return d * v
+		}
+	}
+}
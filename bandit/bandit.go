@@ -0,0 +1,103 @@
+package bandit
+
+import "math/rand"
+
+type Bandit interface {
+	Arms() int
+	Pull(arm int) float64
+	OptimalArm() int
+}
+
+func argmaxSlice(values []float64) int {
+	maxIndex := 0
+	for i, value := range values {
+		if value > values[maxIndex] {
+			maxIndex = i
+		}
+	}
+	return maxIndex
+}
+
+// Gaussian is the 10-armed testbed: arm means are drawn from N(0, 1) and
+// each pull pays the arm mean plus N(0, 1) noise.
+type Gaussian struct {
+	means []float64
+	rng   *rand.Rand
+}
+
+func NewGaussian(k int, rng *rand.Rand) *Gaussian {
+	means := make([]float64, k)
+	for i := range means {
+		means[i] = rng.NormFloat64()
+	}
+	return &Gaussian{means: means, rng: rng}
+}
+
+func (g *Gaussian) Arms() int {
+	return len(g.means)
+}
+
+func (g *Gaussian) Pull(arm int) float64 {
+	return g.means[arm] + g.rng.NormFloat64()
+}
+
+func (g *Gaussian) OptimalArm() int {
+	return argmaxSlice(g.means)
+}
+
+// Bernoulli pays 1 with an arm specific probability drawn uniformly.
+type Bernoulli struct {
+	probs []float64
+	rng   *rand.Rand
+}
+
+func NewBernoulli(k int, rng *rand.Rand) *Bernoulli {
+	probs := make([]float64, k)
+	for i := range probs {
+		probs[i] = rng.Float64()
+	}
+	return &Bernoulli{probs: probs, rng: rng}
+}
+
+func (b *Bernoulli) Arms() int {
+	return len(b.probs)
+}
+
+func (b *Bernoulli) Pull(arm int) float64 {
+	if b.rng.Float64() < b.probs[arm] {
+		return 1.0
+	}
+	return 0.0
+}
+
+func (b *Bernoulli) OptimalArm() int {
+	return argmaxSlice(b.probs)
+}
+
+// Drifting is the nonstationary testbed of exercise 2.5: all means start
+// equal and take an independent N(0, walkStd^2) step after every pull.
+type Drifting struct {
+	means   []float64
+	walkStd float64
+	rng     *rand.Rand
+}
+
+func NewDrifting(k int, walkStd float64, rng *rand.Rand) *Drifting {
+	return &Drifting{means: make([]float64, k), walkStd: walkStd, rng: rng}
+}
+
+func (d *Drifting) Arms() int {
+	return len(d.means)
+}
+
+func (d *Drifting) Pull(arm int) float64 {
+	reward := d.means[arm] + d.rng.NormFloat64()
+	for i := range d.means {
+		d.means[i] += d.walkStd * d.rng.NormFloat64()
+	}
+	return reward
+}
+
+func (d *Drifting) OptimalArm() int {
+	return argmaxSlice(d.means)
+}
@@ -0,0 +1,37 @@
+package bandit
+
+import "math/rand"
+
+type Result struct {
+	AverageReward []float64
+	OptimalAction []float64
+}
+
+// Run plays steps pulls in each of runs independent bandit/agent pairs and
+// averages the reward and the fraction of optimal pulls at every step. Run
+// i is seeded with seed + i.
+func Run(runs int, steps int, seed int64, newBandit func(rng *rand.Rand) Bandit, newAgent func(arms int, rng *rand.Rand) Agent) Result {
+	result := Result{
+		AverageReward: make([]float64, steps),
+		OptimalAction: make([]float64, steps),
+	}
+	for run := 0; run < runs; run++ {
+		rng := rand.New(rand.NewSource(seed + int64(run)))
+		b := newBandit(rng)
+		agent := newAgent(b.Arms(), rng)
+		for t := 0; t < steps; t++ {
+			arm := agent.SelectArm()
+			if arm == b.OptimalArm() {
+				result.OptimalAction[t]++
+			}
+			reward := b.Pull(arm)
+			agent.Update(arm, reward)
+			result.AverageReward[t] += reward
+		}
+	}
+	for t := 0; t < steps; t++ {
+		result.AverageReward[t] /= float64(runs)
+		result.OptimalAction[t] /= float64(runs)
+	}
+	return result
+}
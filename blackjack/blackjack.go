@@ -0,0 +1,125 @@
+package blackjack
+
+import "math/rand"
+
+const (
+	Stick = iota
+	Hit
+)
+
+// naturalReward is paid when the player sticks on a natural, an ace and a
+// ten-card as the first two cards, and the dealer has none. Against a
+// dealer natural it is a draw like any other tie.
+const naturalReward = 1.5
+
+// State is the player sum (12-21), the dealer's showing card (1-10, ace is 1)
+// and whether the player holds a usable ace (0 or 1).
+type State [3]int
+
+func Actions() []int {
+	return []int{Stick, Hit}
+}
+
+type hand struct {
+	sum   int
+	aces  int
+	cards int
+}
+
+func (h *hand) add(card int) {
+	h.sum += card
+	h.cards++
+	if card == 1 {
+		h.aces++
+	}
+}
+
+func (h *hand) usableAce() bool {
+	return h.aces > 0 && h.sum+10 <= 21
+}
+
+func (h *hand) value() int {
+	if h.usableAce() {
+		return h.sum + 10
+	}
+	return h.sum
+}
+
+func (h *hand) natural() bool {
+	return h.cards == 2 && h.value() == 21
+}
+
+// Blackjack is the game of Sutton & Barto's example 5.1 with an infinite
+// deck: face cards count 10 and an ace counts 11 unless that busts the hand.
+type Blackjack struct {
+	player  hand
+	dealer  hand
+	showing int
+	rng     *rand.Rand
+}
+
+func New(rng *rand.Rand) *Blackjack {
+	return &Blackjack{rng: rng}
+}
+
+func (b *Blackjack) drawCard() int {
+	card := b.rng.Intn(13) + 1
+	if card > 10 {
+		return 10
+	}
+	return card
+}
+
+func (b *Blackjack) state() State {
+	usableAce := 0
+	if b.player.usableAce() {
+		usableAce = 1
+	}
+	return State{b.player.value(), b.showing, usableAce}
+}
+
+// Reset deals a new hand. Sums below 12 always hit, so the player keeps
+// drawing until the sum is at least 12.
+func (b *Blackjack) Reset() State {
+	b.player = hand{}
+	b.dealer = hand{}
+	b.player.add(b.drawCard())
+	b.player.add(b.drawCard())
+	b.showing = b.drawCard()
+	b.dealer.add(b.showing)
+	b.dealer.add(b.drawCard())
+	for b.player.value() < 12 {
+		b.player.add(b.drawCard())
+	}
+	return b.state()
+}
+
+// Step hits or sticks and reports whether the game is over. Sticking lets
+// the dealer draw until 17 and settles the game.
+func (b *Blackjack) Step(action int) (State, float64, bool) {
+	if action == Hit {
+		b.player.add(b.drawCard())
+		if b.player.value() > 21 {
+			return b.state(), -1.0, true
+		}
+		return b.state(), 0.0, false
+	}
+	if b.player.natural() {
+		if b.dealer.natural() {
+			return b.state(), 0.0, true
+		}
+		return b.state(), naturalReward, true
+	}
+	for b.dealer.value() < 17 {
+		b.dealer.add(b.drawCard())
+	}
+	playerValue := b.player.value()
+	dealerValue := b.dealer.value()
+	switch {
+	case dealerValue > 21 || playerValue > dealerValue:
+		return b.state(), 1.0, true
+	case playerValue < dealerValue:
+		return b.state(), -1.0, true
+	}
+	return b.state(), 0.0, true
+}
@@ -0,0 +1,150 @@
+package checkpoint
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// The binary format is little-endian throughout:
+//
+//	magic     "RLPC"
+//	version   uint16
+//	kind      uint8, 0 for v, 1 for q and 2 for policy
+//	algorithm uint16 length and bytes
+//	episodes  uint64
+//	actions   uint16 count, each a uint16 length and bytes
+//	entries   uint32 count and uint16 values per entry, then per entry
+//	          int32 x, int32 y and the values as float64
+var magic = [4]byte{'R', 'L', 'P', 'C'}
+
+var kinds = []Kind{KindV, KindQ, KindPolicy}
+
+type binaryWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (b *binaryWriter) write(data any) {
+	if b.err == nil {
+		b.err = binary.Write(b.w, binary.LittleEndian, data)
+	}
+}
+
+func (b *binaryWriter) string(s string) {
+	if len(s) > math.MaxUint16 {
+		b.err = errors.Join(b.err, fmt.Errorf("string of %d bytes is too long", len(s)))
+		return
+	}
+	b.write(uint16(len(s)))
+	b.write([]byte(s))
+}
+
+func WriteBinary(w io.Writer, c *Checkpoint) error {
+	if err := c.validate(); err != nil {
+		return err
+	}
+	kind := 0
+	for i, k := range kinds {
+		if k == c.Kind {
+			kind = i
+		}
+	}
+	width := 1
+	if c.Kind != KindV {
+		width = len(c.Actions)
+	}
+	b := &binaryWriter{w: w}
+	b.write(magic)
+	b.write(uint16(c.Version))
+	b.write(uint8(kind))
+	b.string(c.Algorithm)
+	b.write(uint64(c.Episodes))
+	b.write(uint16(len(c.Actions)))
+	for _, action := range c.Actions {
+		b.string(action)
+	}
+	b.write(uint32(len(c.Entries)))
+	b.write(uint16(width))
+	for _, entry := range c.Entries {
+		b.write([2]int32{int32(entry.State[0]), int32(entry.State[1])})
+		b.write(entry.Values)
+	}
+	return b.err
+}
+
+type binaryReader struct {
+	r   io.Reader
+	err error
+}
+
+func (b *binaryReader) read(data any) {
+	if b.err == nil {
+		b.err = binary.Read(b.r, binary.LittleEndian, data)
+	}
+}
+
+func (b *binaryReader) string() string {
+	var n uint16
+	b.read(&n)
+	data := make([]byte, n)
+	b.read(data)
+	return string(data)
+}
+
+func ReadBinary(r io.Reader) (*Checkpoint, error) {
+	b := &binaryReader{r: r}
+	var header [4]byte
+	b.read(&header)
+	if b.err == nil && header != magic {
+		return nil, errors.New("not a binary checkpoint")
+	}
+	var version uint16
+	b.read(&version)
+	if b.err == nil && (version < 1 || version > Version) {
+		return nil, fmt.Errorf("unsupported checkpoint version %d, this build reads up to %d", version, Version)
+	}
+	var kind uint8
+	b.read(&kind)
+	if b.err == nil && int(kind) >= len(kinds) {
+		return nil, fmt.Errorf("unknown checkpoint kind %d", kind)
+	}
+	c := &Checkpoint{Version: int(version)}
+	if b.err == nil {
+		c.Kind = kinds[kind]
+	}
+	c.Algorithm = b.string()
+	var episodes uint64
+	b.read(&episodes)
+	c.Episodes = int(episodes)
+	var actions uint16
+	b.read(&actions)
+	c.Actions = make([]string, 0, actions)
+	for i := 0; i < int(actions) && b.err == nil; i++ {
+		c.Actions = append(c.Actions, b.string())
+	}
+	var entries uint32
+	var width uint16
+	b.read(&entries)
+	b.read(&width)
+	c.Entries = make([]Entry, 0)
+	for i := 0; i < int(entries) && b.err == nil; i++ {
+		var state [2]int32
+		values := make([]float64, width)
+		b.read(&state)
+		b.read(values)
+		c.Entries = append(c.Entries, Entry{[2]int{int(state[0]), int(state[1])}, values})
+	}
+	if b.err != nil {
+		if errors.Is(b.err, io.EOF) {
+			return nil, io.ErrUnexpectedEOF
+		}
+		return nil, b.err
+	}
+	if err := c.validate(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
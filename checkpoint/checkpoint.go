@@ -0,0 +1,178 @@
+package checkpoint
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Version is the format version written by this package. Readers accept
+// any version up to it.
+const Version = 1
+
+type Kind string
+
+const (
+	KindV      Kind = "v"
+	KindQ      Kind = "q"
+	KindPolicy Kind = "policy"
+)
+
+// Entry holds the values of one state: its value for a V table, and one
+// action value or probability per action, in action order, otherwise.
+type Entry struct {
+	State  [2]int    `json:"state"`
+	Values []float64 `json:"values"`
+}
+
+// Checkpoint is a value table, Q table or policy of a gridworld together
+// with what is needed to resume or evaluate it. Actions names the actions
+// by index; Episodes counts the episodes trained so far.
+type Checkpoint struct {
+	Version   int      `json:"version"`
+	Kind      Kind     `json:"kind"`
+	Algorithm string   `json:"algorithm,omitempty"`
+	Episodes  int      `json:"episodes"`
+	Actions   []string `json:"actions"`
+	Entries   []Entry  `json:"entries"`
+}
+
+func sortEntries(entries []Entry) {
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i].State, entries[j].State
+		if a[1] != b[1] {
+			return a[1] < b[1]
+		}
+		return a[0] < b[0]
+	})
+}
+
+func FromV(v map[[2]int]float64, actions []string) *Checkpoint {
+	entries := make([]Entry, 0, len(v))
+	for state, value := range v {
+		entries = append(entries, Entry{state, []float64{value}})
+	}
+	sortEntries(entries)
+	return &Checkpoint{Version: Version, Kind: KindV, Actions: actions, Entries: entries}
+}
+
+func fromTable(kind Kind, table map[[2]int]map[int]float64, actions []string) *Checkpoint {
+	entries := make([]Entry, 0, len(table))
+	for state, stateValues := range table {
+		values := make([]float64, len(actions))
+		for action := range actions {
+			values[action] = stateValues[action]
+		}
+		entries = append(entries, Entry{state, values})
+	}
+	sortEntries(entries)
+	return &Checkpoint{Version: Version, Kind: kind, Actions: actions, Entries: entries}
+}
+
+func FromQ(q map[[2]int]map[int]float64, actions []string) *Checkpoint {
+	return fromTable(KindQ, q, actions)
+}
+
+func FromPolicy(policy map[[2]int]map[int]float64, actions []string) *Checkpoint {
+	return fromTable(KindPolicy, policy, actions)
+}
+
+func (c *Checkpoint) V() (map[[2]int]float64, error) {
+	if c.Kind != KindV {
+		return nil, fmt.Errorf("checkpoint holds a %s table, not v", c.Kind)
+	}
+	v := make(map[[2]int]float64)
+	for _, entry := range c.Entries {
+		v[entry.State] = entry.Values[0]
+	}
+	return v, nil
+}
+
+func (c *Checkpoint) table(kind Kind) (map[[2]int]map[int]float64, error) {
+	if c.Kind != kind {
+		return nil, fmt.Errorf("checkpoint holds a %s table, not %s", c.Kind, kind)
+	}
+	table := make(map[[2]int]map[int]float64)
+	for _, entry := range c.Entries {
+		table[entry.State] = make(map[int]float64)
+		for action, value := range entry.Values {
+			table[entry.State][action] = value
+		}
+	}
+	return table, nil
+}
+
+func (c *Checkpoint) Q() (map[[2]int]map[int]float64, error) {
+	return c.table(KindQ)
+}
+
+func (c *Checkpoint) Policy() (map[[2]int]map[int]float64, error) {
+	return c.table(KindPolicy)
+}
+
+// validate checks what a reader cannot trust: the version, the kind and the
+// width of every entry.
+func (c *Checkpoint) validate() error {
+	if c.Version < 1 || c.Version > Version {
+		return fmt.Errorf("unsupported checkpoint version %d, this build reads up to %d", c.Version, Version)
+	}
+	width := len(c.Actions)
+	switch c.Kind {
+	case KindV:
+		width = 1
+	case KindQ, KindPolicy:
+	default:
+		return fmt.Errorf("unknown checkpoint kind %q", c.Kind)
+	}
+	for _, entry := range c.Entries {
+		if len(entry.Values) != width {
+			return fmt.Errorf("state %v has %d values, want %d", entry.State, len(entry.Values), width)
+		}
+	}
+	return nil
+}
+
+// Save writes c as JSON when path ends in .json and in the binary format
+// otherwise.
+func Save(path string, c *Checkpoint) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	if filepath.Ext(path) == ".json" {
+		err = WriteJSON(w, c)
+	} else {
+		err = WriteBinary(w, c)
+	}
+	if err == nil {
+		err = w.Flush()
+	}
+	return errors.Join(err, f.Close())
+}
+
+// Load reads a checkpoint in either format, telling them apart by the
+// binary magic.
+func Load(path string) (*Checkpoint, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var c *Checkpoint
+	if bytes.HasPrefix(data, magic[:]) {
+		c, err = ReadBinary(bytes.NewReader(data))
+	} else {
+		c, err = ReadJSON(bytes.NewReader(data))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return c, nil
+}
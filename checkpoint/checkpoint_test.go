@@ -0,0 +1,185 @@
+package checkpoint
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+var actions = []string{"Left", "Right", "Up", "Down"}
+
+func table(offset float64) map[[2]int]map[int]float64 {
+	t := make(map[[2]int]map[int]float64)
+	for _, state := range [][2]int{{0, 0}, {1, 0}, {0, 1}, {2, 3}} {
+		t[state] = make(map[int]float64)
+		for action := range actions {
+			t[state][action] = offset + float64(state[0]) - 0.25*float64(state[1]) + 0.125*float64(action)
+		}
+	}
+	return t
+}
+
+// checkpoints holds one checkpoint of every kind.
+func checkpoints() []*Checkpoint {
+	v := FromV(map[[2]int]float64{{0, 0}: -1.5, {1, 0}: 0, {3, 2}: 0.75}, actions)
+	q := FromQ(table(0), actions)
+	q.Algorithm = "qlearn"
+	q.Episodes = 1200
+	policy := FromPolicy(table(1), actions)
+	policy.Algorithm = "dp pi"
+	return []*Checkpoint{v, q, policy}
+}
+
+type format struct {
+	name  string
+	write func(io.Writer, *Checkpoint) error
+	read  func(io.Reader) (*Checkpoint, error)
+}
+
+var formats = []format{
+	{"json", WriteJSON, ReadJSON},
+	{"binary", WriteBinary, ReadBinary},
+}
+
+func encode(t *testing.T, f format, c *Checkpoint) []byte {
+	t.Helper()
+	var b bytes.Buffer
+	if err := f.write(&b, c); err != nil {
+		t.Fatal(err)
+	}
+	return b.Bytes()
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, f := range formats {
+		for _, c := range checkpoints() {
+			got, err := f.read(bytes.NewReader(encode(t, f, c)))
+			if err != nil {
+				t.Fatalf("%s %s: %v", f.name, c.Kind, err)
+			}
+			if !reflect.DeepEqual(got, c) {
+				t.Errorf("%s %s: read %+v, want %+v", f.name, c.Kind, got, c)
+			}
+		}
+	}
+}
+
+func TestTablesSurviveRoundTrip(t *testing.T) {
+	v := map[[2]int]float64{{0, 0}: -1.5, {2, 1}: 3}
+	q := table(0)
+	policy := table(1)
+	for _, f := range formats {
+		read := func(c *Checkpoint) *Checkpoint {
+			got, err := f.read(bytes.NewReader(encode(t, f, c)))
+			if err != nil {
+				t.Fatalf("%s %s: %v", f.name, c.Kind, err)
+			}
+			return got
+		}
+		if got, err := read(FromV(v, actions)).V(); err != nil || !reflect.DeepEqual(got, v) {
+			t.Errorf("%s: V() = %v, %v, want %v", f.name, got, err, v)
+		}
+		if got, err := read(FromQ(q, actions)).Q(); err != nil || !reflect.DeepEqual(got, q) {
+			t.Errorf("%s: Q() = %v, %v, want %v", f.name, got, err, q)
+		}
+		if got, err := read(FromPolicy(policy, actions)).Policy(); err != nil || !reflect.DeepEqual(got, policy) {
+			t.Errorf("%s: Policy() = %v, %v, want %v", f.name, got, err, policy)
+		}
+	}
+}
+
+func TestSaveAndLoadByExtension(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"q.json", "q.bin"} {
+		path := filepath.Join(dir, "nested", name)
+		c := checkpoints()[1]
+		if err := Save(path, c); err != nil {
+			t.Fatal(err)
+		}
+		got, err := Load(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, c) {
+			t.Errorf("%s: loaded %+v, want %+v", name, got, c)
+		}
+	}
+}
+
+func TestReadRejectsBadMagic(t *testing.T) {
+	data := encode(t, formats[1], checkpoints()[0])
+	copy(data, "RLPX")
+	if _, err := ReadBinary(bytes.NewReader(data)); err == nil || !strings.Contains(err.Error(), "not a binary checkpoint") {
+		t.Fatalf("ReadBinary with a bad magic: %v, want not a binary checkpoint", err)
+	}
+}
+
+func TestReadRejectsUnsupportedVersions(t *testing.T) {
+	for _, version := range []int{0, Version + 1} {
+		c := checkpoints()[1]
+		c.Version = version
+		want := "unsupported checkpoint version"
+		if err := WriteJSON(io.Discard, c); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("WriteJSON version %d: %v, want %q", version, err, want)
+		}
+
+		// Write a supported version and patch the encoding, since the
+		// writers refuse the unsupported one.
+		c.Version = Version
+		jsonData := bytes.Replace(encode(t, formats[0], c), []byte(`"version": 1`), []byte(`"version": `+strconv.Itoa(version)), 1)
+		if _, err := ReadJSON(bytes.NewReader(jsonData)); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("ReadJSON version %d: %v, want %q", version, err, want)
+		}
+		binaryData := encode(t, formats[1], c)
+		binary.LittleEndian.PutUint16(binaryData[4:], uint16(version))
+		if _, err := ReadBinary(bytes.NewReader(binaryData)); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("ReadBinary version %d: %v, want %q", version, err, want)
+		}
+	}
+}
+
+func TestRejectsEntriesOfTheWrongWidth(t *testing.T) {
+	want := "values, want"
+	for _, c := range checkpoints() {
+		c.Entries[1].Values = append(c.Entries[1].Values, 0.5)
+		for _, f := range formats {
+			if err := f.write(io.Discard, c); err == nil || !strings.Contains(err.Error(), want) {
+				t.Errorf("%s %s: writing %v, want a width error", f.name, c.Kind, err)
+			}
+		}
+		// Marshal directly, since WriteJSON refuses the entry.
+		data, err := json.Marshal(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ReadJSON(bytes.NewReader(data)); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("json %s: reading %v, want a width error", c.Kind, err)
+		}
+	}
+
+	// Relabel a V table, one value per entry, as a Q table, which needs
+	// one per action.
+	data := encode(t, formats[1], checkpoints()[0])
+	data[6] = 1
+	if _, err := ReadBinary(bytes.NewReader(data)); err == nil || !strings.Contains(err.Error(), "has 1 values, want 4") {
+		t.Errorf("binary: reading %v, want a width error", err)
+	}
+}
+
+func TestReadBinaryTruncated(t *testing.T) {
+	for _, c := range checkpoints() {
+		data := encode(t, formats[1], c)
+		for n := 0; n < len(data); n++ {
+			if _, err := ReadBinary(bytes.NewReader(data[:n])); !errors.Is(err, io.ErrUnexpectedEOF) {
+				t.Fatalf("%s cut to %d of %d bytes: %v, want %v", c.Kind, n, len(data), err, io.ErrUnexpectedEOF)
+			}
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package checkpoint
+
+import (
+	"encoding/json"
+	"io"
+)
+
+func WriteJSON(w io.Writer, c *Checkpoint) error {
+	if err := c.validate(); err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(c)
+}
+
+func ReadJSON(r io.Reader) (*Checkpoint, error) {
+	var c Checkpoint
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&c); err != nil {
+		return nil, err
+	}
+	if err := c.validate(); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
@@ -0,0 +1,136 @@
+package classiccontrol
+
+import (
+	"math"
+	"math/rand"
+)
+
+const (
+	acrobotDt        = 0.2
+	acrobotLinkLen1  = 1.0
+	acrobotLinkMass1 = 1.0
+	acrobotLinkMass2 = 1.0
+	acrobotLinkCom1  = 0.5
+	acrobotLinkCom2  = 0.5
+	acrobotLinkMoi   = 1.0
+	acrobotMaxVel1   = 4 * math.Pi
+	acrobotMaxVel2   = 9 * math.Pi
+	acrobotGravity   = 9.8
+)
+
+// Acrobot follows Sutton & Barto section 11.3 with the book dynamics and RK4
+// integration of gym's Acrobot-v1. Actions 0, 1 and 2 apply a torque of -1,
+// 0 and +1 to the joint between the links. Every step pays -1 until the tip
+// swings above the bar.
+type Acrobot struct {
+	// Theta1, Theta2, Theta1Dot, Theta2Dot
+	State    [4]float64
+	MaxSteps int
+	steps    int
+	rng      *rand.Rand
+}
+
+func NewAcrobot(rng *rand.Rand) *Acrobot {
+	return &Acrobot{MaxSteps: 500, rng: rng}
+}
+
+func (a *Acrobot) Actions() []int {
+	return []int{0, 1, 2}
+}
+
+func (a *Acrobot) Low() []float64 {
+	return []float64{-1, -1, -1, -1, -acrobotMaxVel1, -acrobotMaxVel2}
+}
+
+func (a *Acrobot) High() []float64 {
+	return []float64{1, 1, 1, 1, acrobotMaxVel1, acrobotMaxVel2}
+}
+
+func (a *Acrobot) observation() []float64 {
+	return []float64{
+		math.Cos(a.State[0]), math.Sin(a.State[0]),
+		math.Cos(a.State[1]), math.Sin(a.State[1]),
+		a.State[2], a.State[3],
+	}
+}
+
+func (a *Acrobot) Reset() []float64 {
+	for i := range a.State {
+		a.State[i] = a.rng.Float64()*0.2 - 0.1
+	}
+	a.steps = 0
+	return a.observation()
+}
+
+func (a *Acrobot) isTerminal() bool {
+	return -math.Cos(a.State[0])-math.Cos(a.State[1]+a.State[0]) > 1.0
+}
+
+func (a *Acrobot) Step(action int) ([]float64, float64, bool, bool) {
+	torque := float64(action - 1)
+	next := rk4(a.State, torque, acrobotDt)
+	next[0] = wrap(next[0], -math.Pi, math.Pi)
+	next[1] = wrap(next[1], -math.Pi, math.Pi)
+	next[2] = clip(next[2], -acrobotMaxVel1, acrobotMaxVel1)
+	next[3] = clip(next[3], -acrobotMaxVel2, acrobotMaxVel2)
+	a.State = next
+	a.steps++
+
+	isGoal := a.isTerminal()
+	reward := -1.0
+	if isGoal {
+		reward = 0.0
+	}
+	truncated := !isGoal && a.MaxSteps > 0 && a.steps >= a.MaxSteps
+	return a.observation(), reward, isGoal, truncated
+}
+
+func acrobotDerivatives(s [4]float64, torque float64) [4]float64 {
+	m1, m2 := acrobotLinkMass1, acrobotLinkMass2
+	l1 := acrobotLinkLen1
+	lc1, lc2 := acrobotLinkCom1, acrobotLinkCom2
+	i1, i2 := acrobotLinkMoi, acrobotLinkMoi
+	g := acrobotGravity
+	theta1, theta2, dtheta1, dtheta2 := s[0], s[1], s[2], s[3]
+
+	d1 := m1*lc1*lc1 + m2*(l1*l1+lc2*lc2+2*l1*lc2*math.Cos(theta2)) + i1 + i2
+	d2 := m2*(lc2*lc2+l1*lc2*math.Cos(theta2)) + i2
+	phi2 := m2 * lc2 * g * math.Cos(theta1+theta2-math.Pi/2)
+	phi1 := -m2*l1*lc2*dtheta2*dtheta2*math.Sin(theta2) -
+		2*m2*l1*lc2*dtheta2*dtheta1*math.Sin(theta2) +
+		(m1*lc1+m2*l1)*g*math.Cos(theta1-math.Pi/2) + phi2
+	ddtheta2 := (torque + d2/d1*phi1 - m2*l1*lc2*dtheta1*dtheta1*math.Sin(theta2) - phi2) /
+		(m2*lc2*lc2 + i2 - d2*d2/d1)
+	ddtheta1 := -(d2*ddtheta2 + phi1) / d1
+	return [4]float64{dtheta1, dtheta2, ddtheta1, ddtheta2}
+}
+
+func rk4(s [4]float64, torque float64, dt float64) [4]float64 {
+	shift := func(base [4]float64, k [4]float64, h float64) [4]float64 {
+		var out [4]float64
+		for i := range base {
+			out[i] = base[i] + h*k[i]
+		}
+		return out
+	}
+	k1 := acrobotDerivatives(s, torque)
+	k2 := acrobotDerivatives(shift(s, k1, dt/2), torque)
+	k3 := acrobotDerivatives(shift(s, k2, dt/2), torque)
+	k4 := acrobotDerivatives(shift(s, k3, dt), torque)
+	var next [4]float64
+	for i := range s {
+		next[i] = s[i] + dt/6*(k1[i]+2*k2[i]+2*k3[i]+k4[i])
+	}
+	return next
+}
+
+func wrap(value, low, high float64) float64 {
+	diff := high - low
+	for value > high {
+		value -= diff
+	}
+	for value < low {
+		value += diff
+	}
+	return value
+}
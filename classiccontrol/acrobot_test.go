@@ -0,0 +1,74 @@
+package classiccontrol
+
+import (
+	"math"
+	"testing"
+)
+
+var acrobotTrajectories = []struct {
+	name    string
+	start   []float64
+	actions []int
+	want    []step
+}{
+	{
+		name:    "swing",
+		start:   []float64{0.05, -0.03, 0.02, 0.01},
+		actions: []int{2, 2, 2, 0, 0, 0, 1, 2, 2, 0},
+		want: []step{
+			{[]float64{0.03396426807716706, 0.014028356136744108, -0.175464650251537, 0.4197156022652691}, false},
+			{[]float64{-0.016231987471804495, 0.12979767440777507, -0.31232424373614626, 0.7094698215459034}, false},
+			{[]float64{-0.08389550882191196, 0.28370050289057835, -0.3455949128225275, 0.7922733806603953}, false},
+			{[]float64{-0.12110402735674738, 0.36471858507207666, -0.018207776211722593, 0.0021595501634864567}, false},
+			{[]float64{-0.09079081330132778, 0.2841830991614987, 0.3136543354871817, -0.7913019132263811}, false},
+			{[]float64{-0.0020293389517137966, 0.06142521695818742, 0.5494187923746385, -1.38533589492386}, false},
+			{[]float64{0.10278475949827245, -0.20869652289276702, 0.4679923016765735, -1.2511569233190687}, false},
+			{[]float64{0.16136010420092264, -0.38234878275143624, 0.10332025118050797, -0.4527095245180289}, false},
+			{[]float64{0.14197110459462978, -0.3836928132524371, -0.2926612805857743, 0.4357949972922621}, false},
+			{[]float64{0.07548275009277615, -0.28336379575610626, -0.3560077552201788, 0.5411060987764993}, false},
+		},
+	},
+	{
+		name:    "fast spin",
+		start:   []float64{3.0, 1.0, 12.0, -27.0},
+		actions: []int{2, 0, 2},
+		want: []step{
+			{[]float64{-1.1001438143275015, 2.440609703798846, 7.118801901294317, -15.218530652502135}, false},
+			{[]float64{0.7290882889421866, -1.5166950234597198, 9.10931055222452, -19.9166378686525}, false},
+			{[]float64{2.232502586097141, 1.4007018248678191, 7.315257602916354, -16.832478907394698}, true},
+		},
+	},
+}
+
+func TestAcrobotTrajectories(t *testing.T) {
+	for _, tt := range acrobotTrajectories {
+		t.Run(tt.name, func(t *testing.T) {
+			a := NewAcrobot(nil)
+			copy(a.State[:], tt.start)
+			for i, action := range tt.actions {
+				observation, reward, terminated, truncated := a.Step(action)
+				wantReward := -1.0
+				if tt.want[i].terminated {
+					wantReward = 0.0
+				}
+				if reward != wantReward {
+					t.Fatalf("step %d: reward %v, want %v", i+1, reward, wantReward)
+				}
+				checkStep(t, i, a.State[:], terminated, truncated, tt.want[i])
+				theta1, theta2 := tt.want[i].state[0], tt.want[i].state[1]
+				wantObservation := []float64{math.Cos(theta1), math.Sin(theta1), math.Cos(theta2), math.Sin(theta2)}
+				for j, want := range wantObservation {
+					if math.Abs(observation[j]-want) > trajectoryTolerance {
+						t.Fatalf("step %d: observation %v, want the cosines and sines of %v", i+1, observation, tt.want[i].state)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestAcrobotTruncation(t *testing.T) {
+	a := NewAcrobot(nil)
+	a.MaxSteps = 4
+	checkTruncation(t, a, 1, 4)
+}
@@ -0,0 +1,85 @@
+package classiccontrol
+
+import (
+	"math"
+	"math/rand"
+)
+
+const (
+	cartPoleGravity        = 9.8
+	cartPoleMassCart       = 1.0
+	cartPoleMassPole       = 0.1
+	cartPoleTotalMass      = cartPoleMassCart + cartPoleMassPole
+	cartPoleLength         = 0.5
+	cartPolePoleMassLength = cartPoleMassPole * cartPoleLength
+	cartPoleForce          = 10.0
+	cartPoleTau            = 0.02
+	cartPoleXThreshold     = 2.4
+	cartPoleThetaThreshold = 12 * 2 * math.Pi / 360
+)
+
+// CartPole follows Barto, Sutton & Anderson (1983) with the constants and
+// explicit Euler integration of gym's CartPole-v1. Actions are 0 (push left)
+// and 1 (push right); every step, including the failing one, pays +1.
+type CartPole struct {
+	X        float64
+	XDot     float64
+	Theta    float64
+	ThetaDot float64
+	MaxSteps int
+	steps    int
+	rng      *rand.Rand
+}
+
+func NewCartPole(rng *rand.Rand) *CartPole {
+	return &CartPole{MaxSteps: 500, rng: rng}
+}
+
+func (c *CartPole) Actions() []int {
+	return []int{0, 1}
+}
+
+func (c *CartPole) Low() []float64 {
+	return []float64{-2 * cartPoleXThreshold, math.Inf(-1), -2 * cartPoleThetaThreshold, math.Inf(-1)}
+}
+
+func (c *CartPole) High() []float64 {
+	return []float64{2 * cartPoleXThreshold, math.Inf(1), 2 * cartPoleThetaThreshold, math.Inf(1)}
+}
+
+func (c *CartPole) observation() []float64 {
+	return []float64{c.X, c.XDot, c.Theta, c.ThetaDot}
+}
+
+func (c *CartPole) Reset() []float64 {
+	c.X = c.rng.Float64()*0.1 - 0.05
+	c.XDot = c.rng.Float64()*0.1 - 0.05
+	c.Theta = c.rng.Float64()*0.1 - 0.05
+	c.ThetaDot = c.rng.Float64()*0.1 - 0.05
+	c.steps = 0
+	return c.observation()
+}
+
+func (c *CartPole) Step(action int) ([]float64, float64, bool, bool) {
+	force := -cartPoleForce
+	if action == 1 {
+		force = cartPoleForce
+	}
+	cosTheta := math.Cos(c.Theta)
+	sinTheta := math.Sin(c.Theta)
+	temp := (force + cartPolePoleMassLength*c.ThetaDot*c.ThetaDot*sinTheta) / cartPoleTotalMass
+	thetaAcc := (cartPoleGravity*sinTheta - cosTheta*temp) /
+		(cartPoleLength * (4.0/3.0 - cartPoleMassPole*cosTheta*cosTheta/cartPoleTotalMass))
+	xAcc := temp - cartPolePoleMassLength*thetaAcc*cosTheta/cartPoleTotalMass
+
+	c.X += cartPoleTau * c.XDot
+	c.XDot += cartPoleTau * xAcc
+	c.Theta += cartPoleTau * c.ThetaDot
+	c.ThetaDot += cartPoleTau * thetaAcc
+	c.steps++
+
+	isFailed := c.X < -cartPoleXThreshold || c.X > cartPoleXThreshold ||
+		c.Theta < -cartPoleThetaThreshold || c.Theta > cartPoleThetaThreshold
+	truncated := !isFailed && c.MaxSteps > 0 && c.steps >= c.MaxSteps
+	return c.observation(), 1.0, isFailed, truncated
+}
@@ -0,0 +1,60 @@
+package classiccontrol
+
+import "testing"
+
+var cartPoleTrajectories = []struct {
+	name    string
+	start   []float64
+	actions []int
+	want    []step
+}{
+	{
+		name:    "balance",
+		start:   []float64{0.01, -0.02, 0.03, 0.04},
+		actions: []int{1, 0, 0, 1, 0, 1, 1, 0, 1, 0},
+		want: []step{
+			{[]float64{0.009600000000000001, 0.17467919574755525, 0.030799999999999998, -0.2430687179600081}, false},
+			{[]float64{0.013093583914951107, -0.020868848948191993, 0.025938625640799837, 0.0591679999394166}, false},
+			{[]float64{0.012676206935987267, -0.21635292104612766, 0.02712198563958817, 0.3599205713784138}, false},
+			{[]float64{0.008349148515064714, -0.021626799199488206, 0.03432039706715644, 0.0759116989474441}, false},
+			{[]float64{0.00791661253107495, -0.21722352452133115, 0.035838631046105324, 0.37922222636906866}, false},
+			{[]float64{0.0035721420406483262, -0.022628364998184547, 0.043423075573486694, 0.09805122376288172}, false},
+			{[]float64{0.003119574740684635, 0.17184521117883425, 0.04538410004874433, -0.18062179188169758}, false},
+			{[]float64{0.00655647896426132, -0.023895796993938623, 0.04177166421111038, 0.12602573947669926}, false},
+			{[]float64{0.0060785630243825476, 0.17060361312834738, 0.04429217900064437, -0.15319158212851092}, false},
+			{[]float64{0.009490635286949496, -0.025123631036058275, 0.04122834735807415, 0.15312899165994215}, false},
+		},
+	},
+	{
+		name:    "falls right",
+		start:   []float64{0.0, 0.0, 0.18, 0.5},
+		actions: []int{0, 0, 0},
+		want: []step{
+			{[]float64{0.0, -0.19714200453251385, 0.19, 0.8435700712015739}, false},
+			{[]float64{-0.003942840090650277, -0.3942781190327799, 0.2068714014240315, 1.1894773353254016}, false},
+			{[]float64{-0.011828402471305875, -0.5913909790507268, 0.23066094813053953, 1.539229769165216}, true},
+		},
+	},
+}
+
+func TestCartPoleTrajectories(t *testing.T) {
+	for _, tt := range cartPoleTrajectories {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewCartPole(nil)
+			c.X, c.XDot, c.Theta, c.ThetaDot = tt.start[0], tt.start[1], tt.start[2], tt.start[3]
+			for i, action := range tt.actions {
+				observation, reward, terminated, truncated := c.Step(action)
+				if reward != 1.0 {
+					t.Fatalf("step %d: reward %v, want 1", i+1, reward)
+				}
+				checkStep(t, i, observation, terminated, truncated, tt.want[i])
+			}
+		})
+	}
+}
+
+func TestCartPoleTruncation(t *testing.T) {
+	c := NewCartPole(nil)
+	c.MaxSteps = 3
+	checkTruncation(t, c, 0, 3)
+}
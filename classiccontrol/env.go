@@ -0,0 +1,24 @@
+package classiccontrol
+
+// Env is the Reset/Step contract of the gridworld agents with continuous
+// observations: Step returns the next observation, the reward, whether a
+// terminal state was reached and whether the episode was cut off at MaxSteps
+// instead. Only termination ends the return; a truncated transition should
+// still bootstrap from the next observation.
+type Env interface {
+	Reset() []float64
+	Step(action int) ([]float64, float64, bool, bool)
+	Actions() []int
+	Low() []float64
+	High() []float64
+}
+
+func clip(value, low, high float64) float64 {
+	if value < low {
+		return low
+	}
+	if value > high {
+		return high
+	}
+	return value
+}
@@ -0,0 +1,47 @@
+package classiccontrol
+
+import (
+	"math"
+	"testing"
+)
+
+// The reference trajectories in these tests were computed with the step
+// functions of gym's CartPole-v1, MountainCar-v0 and Acrobot-v1 from the
+// same start states and action sequences.
+
+type step struct {
+	state      []float64
+	terminated bool
+}
+
+const trajectoryTolerance = 1e-9
+
+func checkStep(t *testing.T, i int, got []float64, terminated bool, truncated bool, want step) {
+	t.Helper()
+	for j := range want.state {
+		if math.Abs(got[j]-want.state[j]) > trajectoryTolerance {
+			t.Fatalf("step %d: state %v, want %v", i+1, got, want.state)
+		}
+	}
+	if terminated != want.terminated {
+		t.Fatalf("step %d: terminated %v, want %v", i+1, terminated, want.terminated)
+	}
+	if truncated {
+		t.Fatalf("step %d: truncated before MaxSteps", i+1)
+	}
+}
+
+// checkTruncation steps env with action until MaxSteps and expects only the
+// last step to report truncation, and never termination.
+func checkTruncation(t *testing.T, env Env, action int, maxSteps int) {
+	t.Helper()
+	for i := 1; i <= maxSteps; i++ {
+		_, _, terminated, truncated := env.Step(action)
+		if terminated {
+			t.Fatalf("step %d: terminated, want a state that survives %d steps", i, maxSteps)
+		}
+		if truncated != (i == maxSteps) {
+			t.Fatalf("step %d: truncated %v with MaxSteps %d", i, truncated, maxSteps)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package classiccontrol
+
+import (
+	"math"
+	"math/rand"
+)
+
+const (
+	mountainCarMinPosition = -1.2
+	mountainCarMaxPosition = 0.6
+	mountainCarMaxSpeed    = 0.07
+	mountainCarGoal        = 0.5
+	mountainCarForce       = 0.001
+	mountainCarGravity     = 0.0025
+)
+
+// MountainCar follows Sutton & Barto example 10.1. Actions are 0 (full
+// throttle reverse), 1 (zero throttle) and 2 (full throttle forward). As in
+// gym, the episode terminates at the goal position only when not rolling back.
+type MountainCar struct {
+	Position float64
+	Velocity float64
+	MaxSteps int
+	steps    int
+	rng      *rand.Rand
+}
+
+func NewMountainCar(rng *rand.Rand) *MountainCar {
+	return &MountainCar{MaxSteps: 200, rng: rng}
+}
+
+func (m *MountainCar) Actions() []int {
+	return []int{0, 1, 2}
+}
+
+func (m *MountainCar) Low() []float64 {
+	return []float64{mountainCarMinPosition, -mountainCarMaxSpeed}
+}
+
+func (m *MountainCar) High() []float64 {
+	return []float64{mountainCarMaxPosition, mountainCarMaxSpeed}
+}
+
+func (m *MountainCar) Reset() []float64 {
+	m.Position = -0.6 + m.rng.Float64()*0.2
+	m.Velocity = 0.0
+	m.steps = 0
+	return []float64{m.Position, m.Velocity}
+}
+
+func (m *MountainCar) Step(action int) ([]float64, float64, bool, bool) {
+	m.Velocity += float64(action-1)*mountainCarForce - math.Cos(3*m.Position)*mountainCarGravity
+	m.Velocity = clip(m.Velocity, -mountainCarMaxSpeed, mountainCarMaxSpeed)
+	m.Position += m.Velocity
+	m.Position = clip(m.Position, mountainCarMinPosition, mountainCarMaxPosition)
+	if m.Position == mountainCarMinPosition && m.Velocity < 0 {
+		m.Velocity = 0.0
+	}
+	m.steps++
+	isGoal := m.Position >= mountainCarGoal && m.Velocity >= 0
+	truncated := !isGoal && m.MaxSteps > 0 && m.steps >= m.MaxSteps
+	return []float64{m.Position, m.Velocity}, -1.0, isGoal, truncated
+}
@@ -0,0 +1,78 @@
+package classiccontrol
+
+import "testing"
+
+var mountainCarTrajectories = []struct {
+	name    string
+	start   []float64
+	actions []int
+	want    []step
+}{
+	{
+		name:    "valley",
+		start:   []float64{-0.5, 0.0},
+		actions: []int{2, 2, 2, 1, 1, 0, 0, 0, 2, 2},
+		want: []step{
+			{[]float64{-0.49917684300416926, 0.0008231569958307428}, false},
+			{[]float64{-0.49753668667935325, 0.0016401563248160246}, false},
+			{[]float64{-0.4950917969323474, 0.002444889747005863}, false},
+			{[]float64{-0.4928604490016134, 0.0022313479307339793}, false},
+			{[]float64{-0.4908593119261084, 0.0020011370755050524}, false},
+			{[]float64{-0.4901033262275611, 0.0007559856985472689}, false},
+			{[]float64{-0.4905981338370592, -0.0004948076094981203}, false},
+			{[]float64{-0.49234004214302496, -0.001741908305965786}, false},
+			{[]float64{-0.4933160474902787, -0.0009760053472537357}, false},
+			{[]float64{-0.4935188610081882, -0.00020281351790950753}, false},
+		},
+	},
+	{
+		name:    "left wall",
+		start:   []float64{-1.18, -0.03},
+		actions: []int{0, 0, 2},
+		want: []step{
+			{[]float64{-1.2, 0}, false},
+			{[]float64{-1.1987581039591646, 0.0012418960408353682}, false},
+			{[]float64{-1.194270205713714, 0.004487898245450696}, false},
+		},
+	},
+	{
+		name:    "reaches goal",
+		start:   []float64{0.48, 0.03},
+		actions: []int{2, 2},
+		want: []step{
+			{[]float64{0.5106739407281546, 0.030673940728154634}, true},
+			{[]float64{0.5422509694829583, 0.03157702875480362}, true},
+		},
+	},
+	{
+		name:    "past goal rolling back",
+		start:   []float64{0.58, -0.01},
+		actions: []int{0},
+		want: []step{
+			{[]float64{0.5694209936198726, -0.010579006380127308}, false},
+		},
+	},
+}
+
+func TestMountainCarTrajectories(t *testing.T) {
+	for _, tt := range mountainCarTrajectories {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMountainCar(nil)
+			m.Position, m.Velocity = tt.start[0], tt.start[1]
+			for i, action := range tt.actions {
+				observation, reward, terminated, truncated := m.Step(action)
+				if reward != -1.0 {
+					t.Fatalf("step %d: reward %v, want -1", i+1, reward)
+				}
+				checkStep(t, i, observation, terminated, truncated, tt.want[i])
+			}
+		})
+	}
+}
+
+func TestMountainCarTruncation(t *testing.T) {
+	m := NewMountainCar(nil)
+	m.Position = -0.5
+	m.MaxSteps = 5
+	checkTruncation(t, m, 1, 5)
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"slices"
+
+	"reinforcement-learning-playground/checkpoint"
+	"reinforcement-learning-playground/explorer"
+	"reinforcement-learning-playground/metrics"
+)
+
+func actionNames(env *Env) []string {
+	names := make([]string, 0)
+	for _, action := range env.Actions() {
+		names = append(names, env.world.ActionName(action))
+	}
+	return names
+}
+
+// loadCheckpoint reads path and checks that it was saved on a map with the
+// same actions and open blocks as env.
+func loadCheckpoint(path string, env *Env) (*checkpoint.Checkpoint, error) {
+	c, err := checkpoint.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Equal(c.Actions, actionNames(env)) {
+		return nil, fmt.Errorf("%s: actions %v do not match %v", path, c.Actions, actionNames(env))
+	}
+	saved := make([][2]int, 0)
+	for _, entry := range c.Entries {
+		saved = append(saved, entry.State)
+	}
+	if !slices.Equal(saved, env.States()) {
+		return nil, fmt.Errorf("%s: states do not match the map", path)
+	}
+	return c, nil
+}
+
+// offsetEpisodes numbers the episodes of a resumed run after the ones of
+// its checkpoint.
+func offsetEpisodes(hook episodeHook, offset int) episodeHook {
+	return func(e metrics.Episode) error {
+		e.Episode += offset
+		return hook(e)
+	}
+}
+
+func qPolicy(q map[[2]int]map[int]float64) Policy {
+	policy := make(Policy)
+	for state, stateQ := range q {
+		maxActions := explorer.ArgmaxAll(stateQ)
+		policy[state] = make(map[int]float64)
+		for _, action := range maxActions {
+			policy[state][action] = 1.0 / float64(len(maxActions))
+		}
+	}
+	return policy
+}
+
+// checkpointPolicy is the policy a checkpoint acts by: greedy in q, greedy
+// in v by a one-step lookahead with gamma, or the saved policy itself.
+func checkpointPolicy(env *Env, c *checkpoint.Checkpoint, gamma float64) (Policy, error) {
+	switch c.Kind {
+	case checkpoint.KindV:
+		v, err := c.V()
+		return greedyPolicy(env, v, gamma), err
+	case checkpoint.KindQ:
+		q, err := c.Q()
+		return qPolicy(q), err
+	default:
+		policy, err := c.Policy()
+		return policy, err
+	}
+}
+
+// evaluate runs the policy of the checkpoint for o.episodes episodes without
+// learning and reports its mean return and length and how often it reached
+// the goal.
+func evaluate(o options, env *Env, w io.Writer) error {
+	if o.loadPath == "" {
+		return errors.New("evaluate needs -load")
+	}
+	c, err := loadCheckpoint(o.loadPath, env)
+	if err != nil {
+		return err
+	}
+	policy, err := checkpointPolicy(env, c, o.gamma)
+	if err != nil {
+		return err
+	}
+	rng := rand.New(rand.NewSource(o.seed))
+	totalReward, totalLength, goals := 0.0, 0, 0
+	for i := 0; i < o.episodes; i++ {
+		state := env.start
+		for t := 1; ; t++ {
+			action, err := sampleAction(policy[state], env.Actions(), rng)
+			if err != nil {
+				return err
+			}
+			nextState, reward, isGoal := env.Step(state, action)
+			totalReward += reward
+			totalLength++
+			if isGoal {
+				goals++
+			}
+			if isGoal || t >= o.maxSteps {
+				break
+			}
+			state = nextState
+		}
+	}
+
+	fmt.Fprintln(w, "=========================================")
+	fmt.Fprintln(w, "Dungeon:")
+	printMap(w, env)
+	fmt.Fprintln(w, "=========================================")
+	fmt.Fprintf(w, "%s checkpoint of %s after %d episodes\n", c.Kind, c.Algorithm, c.Episodes)
+	fmt.Fprintln(w, "policy:")
+	printPolicy(w, env, policy)
+	if o.episodes > 0 {
+		n := float64(o.episodes)
+		fmt.Fprintf(w, "%d episodes: return %.3f  length %.1f  goal %.1f%%\n", o.episodes, totalReward/n, float64(totalLength)/n, 100*float64(goals)/n)
+	}
+	fmt.Fprintln(w, "=========================================")
+	return nil
+}
@@ -0,0 +1,284 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"reinforcement-learning-playground/gridworld"
+)
+
+// Config declares an experiment for the run command, for example
+//
+//	{
+//	  "name": "cliff-qlearn",
+//	  "algorithm": "qlearn",
+//	  "env": {"map": "cliff.map", "maxSteps": 500},
+//	  "hyperparameters": {"gamma": 1, "alpha": 0.5, "epsilon": 0.1, "episodes": 500},
+//	  "runs": 5,
+//	  "seed": 100,
+//	  "output": "results/cliff-qlearn"
+//	}
+//
+// Missing hyperparameters keep the defaults of the command-line flags. The
+// algorithm is one of the command names, e.g. "dp vi" or "sarsa". The
+// environment is either a map file or the rows of an inline grid; without
+// either the experiments' dungeon is used. Relative map and output paths
+// are taken from the directory of the config file.
+// Seeds are listed explicitly or derived as seed, seed+1, ... for runs,
+// which defaults to the number of seeds or one.
+// Policy gives the action probabilities that dp eval and td evaluate in
+// every state, uniform when omitted. With an output directory the learners
+// snapshot their value table every snapshotEvery episodes.
+type Config struct {
+	Name            string             `json:"name"`
+	Algorithm       string             `json:"algorithm"`
+	Env             EnvConfig          `json:"env"`
+	Hyperparameters Hyperparameters    `json:"hyperparameters"`
+	Policy          map[string]float64 `json:"policy,omitempty"`
+	Runs            int                `json:"runs"`
+	Seed            int64              `json:"seed"`
+	Seeds           []int64            `json:"seeds,omitempty"`
+	Output          string             `json:"output,omitempty"`
+	SnapshotEvery   int                `json:"snapshotEvery"`
+}
+
+type EnvConfig struct {
+	Map      string   `json:"map,omitempty"`
+	Grid     []string `json:"grid,omitempty"`
+	MaxSteps int      `json:"maxSteps"`
+}
+
+type Hyperparameters struct {
+	Gamma    float64 `json:"gamma"`
+	Theta    float64 `json:"theta"`
+	Alpha    float64 `json:"alpha"`
+	Epsilon  float64 `json:"epsilon"`
+	Episodes int     `json:"episodes"`
+}
+
+func defaultConfig() Config {
+	return Config{
+		Env: EnvConfig{MaxSteps: defaultOptions.maxSteps},
+		Hyperparameters: Hyperparameters{
+			Gamma:    defaultOptions.gamma,
+			Theta:    defaultOptions.theta,
+			Alpha:    defaultOptions.alpha,
+			Epsilon:  defaultOptions.epsilon,
+			Episodes: defaultOptions.episodes,
+		},
+		SnapshotEvery: defaultOptions.snapshotEvery,
+	}
+}
+
+// loadConfig decodes path over the defaults, rejecting unknown fields, and
+// resolves the map file, the output directory and the seeds so that the returned config describes
+// the experiment completely.
+func loadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	config := defaultConfig()
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&config); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	config.Env.Map = relativeTo(path, config.Env.Map)
+	config.Output = relativeTo(path, config.Output)
+	if config.Runs == 0 {
+		config.Runs = max(len(config.Seeds), 1)
+	}
+	if len(config.Seeds) == 0 {
+		for i := 0; i < config.Runs; i++ {
+			config.Seeds = append(config.Seeds, config.Seed+int64(i))
+		}
+	}
+	return &config, nil
+}
+
+// relativeTo resolves a relative path from the config file at configPath.
+func relativeTo(configPath, path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(filepath.Dir(configPath), path)
+}
+
+func (c *Config) options(seed int64) options {
+	o := options{
+		gamma:    c.Hyperparameters.Gamma,
+		theta:    c.Hyperparameters.Theta,
+		alpha:    c.Hyperparameters.Alpha,
+		epsilon:  c.Hyperparameters.Epsilon,
+		episodes: c.Hyperparameters.Episodes,
+		maxSteps: c.Env.MaxSteps,
+		seed:     seed,
+		mapFile:  c.Env.Map,
+
+		snapshotEvery: c.SnapshotEvery,
+	}
+	if c.Output != "" {
+		o.metricsDir = filepath.Join(c.Output, fmt.Sprintf("seed-%d", seed))
+		o.savePath = filepath.Join(o.metricsDir, "checkpoint.json")
+	}
+	return o
+}
+
+// Validate reports every problem of the config at once.
+func (c *Config) Validate() error {
+	errs := make([]error, 0)
+	if !isKnown(c.Algorithm) {
+		errs = append(errs, fmt.Errorf("unknown algorithm %q", c.Algorithm))
+	}
+	if c.Env.Map != "" && len(c.Env.Grid) > 0 {
+		errs = append(errs, errors.New("env sets both map and grid"))
+	}
+	o := c.options(0)
+	if err := o.validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if c.Hyperparameters.Theta <= 0 {
+		errs = append(errs, fmt.Errorf("theta must be positive, got %v", c.Hyperparameters.Theta))
+	}
+	if c.Runs < 1 {
+		errs = append(errs, fmt.Errorf("runs must be at least 1, got %d", c.Runs))
+	}
+	if len(c.Seeds) != c.Runs {
+		errs = append(errs, fmt.Errorf("%d seeds given for %d runs", len(c.Seeds), c.Runs))
+	}
+	seen := make(map[int64]bool)
+	for _, seed := range c.Seeds {
+		if seen[seed] {
+			errs = append(errs, fmt.Errorf("seed %d is repeated", seed))
+		}
+		seen[seed] = true
+	}
+	if c.Policy != nil {
+		if c.Algorithm != "dp eval" && c.Algorithm != "td" {
+			errs = append(errs, fmt.Errorf("policy is only used by dp eval and td, not %q", c.Algorithm))
+		}
+		errs = append(errs, validatePolicy(c.Policy)...)
+	}
+	return errors.Join(errs...)
+}
+
+func validatePolicy(policy map[string]float64) []error {
+	errs := make([]error, 0)
+	names := make(map[string]bool)
+	for _, name := range gridworld.FourWay.Names {
+		names[name] = true
+	}
+	sum := 0.0
+	for name, prob := range policy {
+		if !names[name] {
+			errs = append(errs, fmt.Errorf("policy has unknown action %q", name))
+		}
+		if prob < 0 || prob > 1 {
+			errs = append(errs, fmt.Errorf("policy probability of %s must be in [0, 1], got %v", name, prob))
+		}
+		sum += prob
+	}
+	if math.Abs(sum-1.0) > 1e-9 {
+		errs = append(errs, fmt.Errorf("policy probabilities sum to %v, not 1", sum))
+	}
+	return errs
+}
+
+func (c *Config) env() (*Env, error) {
+	if len(c.Env.Grid) > 0 {
+		dungeon, err := parseMap(strings.NewReader(strings.Join(c.Env.Grid, "\n")))
+		if err != nil {
+			return nil, err
+		}
+		return newEnv(dungeon)
+	}
+	dungeon, err := loadMap(c.Env.Map)
+	if err != nil {
+		return nil, err
+	}
+	return newEnv(dungeon)
+}
+
+func (c *Config) policy(env *Env) Policy {
+	if c.Policy == nil {
+		return newPolicy(env)
+	}
+	policy := make(Policy)
+	for _, state := range env.States() {
+		policy[state] = make(map[int]float64)
+		for _, action := range env.Actions() {
+			policy[state][action] = c.Policy[env.world.ActionName(action)]
+		}
+	}
+	return policy
+}
+
+// runConfig runs every seed of the config. With an output directory the
+// resolved config is written there as config.json next to one result file
+// per seed, and every seed logs its metrics and saves its checkpoint into a
+// seed-<n> directory.
+func runConfig(args []string, w io.Writer) error {
+	fs := flag.NewFlagSet("run", flag.ContinueOnError)
+	path := fs.String("config", "", "JSON experiment config (YAML is not supported)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *path == "" {
+		return errors.New("run needs -config")
+	}
+	config, err := loadConfig(*path)
+	if err != nil {
+		return err
+	}
+	if err := config.Validate(); err != nil {
+		return fmt.Errorf("%s: %w", *path, err)
+	}
+	env, err := config.env()
+	if err != nil {
+		return err
+	}
+	if config.Output != "" {
+		if err := os.MkdirAll(config.Output, 0o755); err != nil {
+			return err
+		}
+		data, err := json.MarshalIndent(config, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(config.Output, "config.json"), append(data, '\n'), 0o644); err != nil {
+			return err
+		}
+	}
+
+	for i, seed := range config.Seeds {
+		fmt.Fprintf(w, "%s run %d/%d, seed %d\n", config.Name, i+1, len(config.Seeds), seed)
+		out := w
+		var f *os.File
+		if config.Output != "" {
+			f, err = os.Create(filepath.Join(config.Output, fmt.Sprintf("seed-%d.txt", seed)))
+			if err != nil {
+				return err
+			}
+			out = io.MultiWriter(w, f)
+		}
+		err = execute(config.Algorithm, config.options(seed), env, config.policy(env), out)
+		if f != nil {
+			if closeErr := f.Close(); err == nil {
+				err = closeErr
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
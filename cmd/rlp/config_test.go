@@ -0,0 +1,130 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// validConfig is a complete config as loadConfig returns it.
+func validConfig() Config {
+	c := defaultConfig()
+	c.Algorithm = "qlearn"
+	c.Runs = 2
+	c.Seeds = []int64{0, 1}
+	return c
+}
+
+func TestValidate(t *testing.T) {
+	cases := []struct {
+		name   string
+		modify func(c *Config)
+		want   []string
+	}{
+		{"valid", func(c *Config) {}, nil},
+		{"valid td policy", func(c *Config) {
+			c.Algorithm = "td"
+			c.Policy = map[string]float64{"Left": 0.5, "Right": 0.5}
+		}, nil},
+		{"unknown algorithm", func(c *Config) { c.Algorithm = "dqn" }, []string{`unknown algorithm "dqn"`}},
+		{"map and grid", func(c *Config) {
+			c.Env.Map = "cliff.map"
+			c.Env.Grid = []string{"SG"}
+		}, []string{"both map and grid"}},
+		{"gamma", func(c *Config) { c.Hyperparameters.Gamma = 1.5 }, []string{"gamma must be in [0, 1]"}},
+		{"epsilon", func(c *Config) { c.Hyperparameters.Epsilon = -0.1 }, []string{"epsilon must be in [0, 1]"}},
+		{"alpha", func(c *Config) { c.Hyperparameters.Alpha = -1 }, []string{"alpha must not be negative"}},
+		{"episodes", func(c *Config) { c.Hyperparameters.Episodes = -1 }, []string{"must not be negative"}},
+		{"max steps", func(c *Config) { c.Env.MaxSteps = 0 }, []string{"max-steps must be at least 1"}},
+		{"theta", func(c *Config) { c.Hyperparameters.Theta = 0 }, []string{"theta must be positive"}},
+		{"runs", func(c *Config) {
+			c.Runs = 0
+			c.Seeds = nil
+		}, []string{"runs must be at least 1"}},
+		{"seed count", func(c *Config) { c.Seeds = []int64{0} }, []string{"1 seeds given for 2 runs"}},
+		{"repeated seed", func(c *Config) { c.Seeds = []int64{3, 3} }, []string{"seed 3 is repeated"}},
+		{"policy for a learner", func(c *Config) {
+			c.Policy = map[string]float64{"Left": 1}
+		}, []string{`policy is only used by dp eval and td, not "qlearn"`}},
+		{"policy action", func(c *Config) {
+			c.Algorithm = "dp eval"
+			c.Policy = map[string]float64{"Left": 0.5, "Jump": 0.5}
+		}, []string{`unknown action "Jump"`}},
+		{"policy probability", func(c *Config) {
+			c.Algorithm = "td"
+			c.Policy = map[string]float64{"Left": 1.5, "Right": -0.5}
+		}, []string{"probability of Left must be in [0, 1]", "probability of Right must be in [0, 1]"}},
+		{"policy sum", func(c *Config) {
+			c.Algorithm = "td"
+			c.Policy = map[string]float64{"Left": 0.5, "Right": 0.25}
+		}, []string{"sum to 0.75, not 1"}},
+		{"every error at once", func(c *Config) {
+			c.Algorithm = "dqn"
+			c.Env.Grid = []string{"SG"}
+			c.Env.Map = "cliff.map"
+			c.Hyperparameters.Theta = -1
+			c.Seeds = []int64{5, 5, 6}
+		}, []string{"unknown algorithm", "both map and grid", "theta must be positive", "3 seeds given for 2 runs", "seed 5 is repeated"}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			config := validConfig()
+			c.modify(&config)
+			err := config.Validate()
+			if c.want == nil {
+				if err != nil {
+					t.Fatalf("Validate() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("Validate() = nil, want errors containing %q", c.want)
+			}
+			for _, want := range c.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Validate() = %q, want it to contain %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseMap(t *testing.T) {
+	cases := []struct {
+		name string
+		grid string
+		want string
+	}{
+		{"ragged rows", "S..\n..G.", "map row 2 has 4 blocks, expected 3"},
+		{"empty", "# only a comment\n\n", "map is empty"},
+		{"unknown block", "S?G", `unknown block '?' at (1, 0)`},
+		{"no goal", "S..", "found 1 and 0"},
+		{"two starts", "S.S\n..G", "found 2 and 1"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := parseMap(strings.NewReader(c.grid))
+			if err == nil || !strings.Contains(err.Error(), c.want) {
+				t.Fatalf("parseMap(%q) error = %v, want %q", c.grid, err, c.want)
+			}
+		})
+	}
+}
+
+func TestParseMapBlocks(t *testing.T) {
+	dungeon, err := parseMap(strings.NewReader("# comment\n  ...G \n\n.X.T\nS...\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if x, y, _ := dungeon.GetStart(); x != 0 || y != 2 {
+		t.Errorf("start = (%d, %d), want (0, 2)", x, y)
+	}
+	if x, y, _ := dungeon.GetGoal(); x != 3 || y != 0 {
+		t.Errorf("goal = (%d, %d), want (3, 0)", x, y)
+	}
+	if dungeon.IsAvailable(1, 1) {
+		t.Error("obstacle at (1, 1) is available")
+	}
+	if reward := dungeon.Blocks[1][3].Attributes["reward"]; reward != -1.0 {
+		t.Errorf("trap reward = %v, want -1", reward)
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"math"
+
+	"reinforcement-learning-playground/explorer"
+)
+
+type Policy map[[2]int]map[int]float64
+
+func newPolicy(env *Env) Policy {
+	policy := make(Policy)
+	for _, state := range env.States() {
+		statePolicy := make(map[int]float64)
+		for _, action := range env.Actions() {
+			statePolicy[action] = 1.0 / float64(len(env.Actions()))
+		}
+		policy[state] = statePolicy
+	}
+	return policy
+}
+
+func newV(env *Env) map[[2]int]float64 {
+	v := make(map[[2]int]float64)
+	for _, state := range env.States() {
+		v[state] = 0.0
+	}
+	return v
+}
+
+func actionValues(env *Env, v map[[2]int]float64, state [2]int, gamma float64) map[int]float64 {
+	values := make(map[int]float64)
+	for _, action := range env.Actions() {
+		nextState, reward, isGoal := env.Step(state, action)
+		if isGoal {
+			values[action] = reward
+		} else {
+			values[action] = reward + gamma*v[nextState]
+		}
+	}
+	return values
+}
+
+// evaluatePolicy sweeps the Bellman expectation equation in place until no
+// value moves by more than theta and returns the number of sweeps.
+func evaluatePolicy(env *Env, policy Policy, v map[[2]int]float64, gamma float64, theta float64) int {
+	for sweep := 1; ; sweep++ {
+		delta := 0.0
+		for _, state := range env.States() {
+			if env.IsGoal(state) {
+				continue
+			}
+			newV := 0.0
+			for action, value := range actionValues(env, v, state, gamma) {
+				newV += policy[state][action] * value
+			}
+			delta = math.Max(delta, math.Abs(newV-v[state]))
+			v[state] = newV
+		}
+		if delta < theta {
+			return sweep
+		}
+	}
+}
+
+// greedyPolicy spreads the probability evenly over the actions tied for the
+// best one-step lookahead value.
+func greedyPolicy(env *Env, v map[[2]int]float64, gamma float64) Policy {
+	policy := make(Policy)
+	for _, state := range env.States() {
+		maxActions := explorer.ArgmaxAll(actionValues(env, v, state, gamma))
+		policy[state] = make(map[int]float64)
+		for _, action := range env.Actions() {
+			policy[state][action] = 0.0
+		}
+		for _, action := range maxActions {
+			policy[state][action] = 1.0 / float64(len(maxActions))
+		}
+	}
+	return policy
+}
+
+func valueIteration(env *Env, v map[[2]int]float64, gamma float64, theta float64) int {
+	for sweep := 1; ; sweep++ {
+		delta := 0.0
+		for _, state := range env.States() {
+			if env.IsGoal(state) {
+				continue
+			}
+			values := actionValues(env, v, state, gamma)
+			newV := values[explorer.ArgmaxAll(values)[0]]
+			delta = math.Max(delta, math.Abs(newV-v[state]))
+			v[state] = newV
+		}
+		if delta < theta {
+			return sweep
+		}
+	}
+}
+
+func samePolicy(a, b Policy) bool {
+	for state, statePolicy := range a {
+		for action, prob := range statePolicy {
+			if b[state][action] != prob {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// policyIteration alternates full evaluation and greedy improvement until
+// the policy stops changing and returns the number of improvements.
+func policyIteration(env *Env, v map[[2]int]float64, gamma float64, theta float64) (Policy, int) {
+	policy := newPolicy(env)
+	for iteration := 1; ; iteration++ {
+		evaluatePolicy(env, policy, v, gamma, theta)
+		improved := greedyPolicy(env, v, gamma)
+		if samePolicy(policy, improved) {
+			return improved, iteration
+		}
+		policy = improved
+	}
+}
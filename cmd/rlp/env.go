@@ -0,0 +1,65 @@
+package main
+
+import (
+	"github.com/marubontan/go-maze/maze"
+	"reinforcement-learning-playground/gridworld"
+)
+
+// Env is a gridworld with the rewards of a parsed map: 1 for entering the
+// goal, which ends the episode, and the block's "reward" attribute
+// otherwise.
+type Env struct {
+	world *gridworld.World
+	start [2]int
+	goal  [2]int
+}
+
+func newEnv(dungeon *maze.Maze) (*Env, error) {
+	startX, startY, err := dungeon.GetStart()
+	if err != nil {
+		return nil, err
+	}
+	goalX, goalY, err := dungeon.GetGoal()
+	if err != nil {
+		return nil, err
+	}
+	return &Env{
+		world: gridworld.New(dungeon, gridworld.FourWay),
+		start: [2]int{startX, startY},
+		goal:  [2]int{goalX, goalY},
+	}, nil
+}
+
+func (e *Env) Actions() []int {
+	return e.world.Actions()
+}
+
+// States lists every block that is not an obstacle, row by row.
+func (e *Env) States() [][2]int {
+	states := make([][2]int, 0)
+	for y, row := range e.world.Maze.Blocks {
+		for x := range row {
+			if e.world.Maze.IsAvailable(x, y) {
+				states = append(states, [2]int{x, y})
+			}
+		}
+	}
+	return states
+}
+
+func (e *Env) IsGoal(state [2]int) bool {
+	return state == e.goal
+}
+
+func (e *Env) Reward(state [2]int) float64 {
+	if e.IsGoal(state) {
+		return 1.0
+	}
+	reward, _ := e.world.Maze.Blocks[state[1]][state[0]].Attributes["reward"].(float64)
+	return reward
+}
+
+func (e *Env) Step(state [2]int, action int) ([2]int, float64, bool) {
+	nextState := e.world.NextState(state, action)
+	return nextState, e.Reward(nextState), e.IsGoal(nextState)
+}
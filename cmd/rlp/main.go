@@ -0,0 +1,286 @@
+// Command rlp runs the tabular gridworld algorithms of the playground on a
+// map file with hyperparameters given as flags.
+//
+//	rlp dp eval|vi|pi [flags]
+//	rlp mc|td|sarsa|qlearn [flags]
+//	rlp run -config experiment.json
+//	rlp evaluate -load checkpoint.json [flags]
+//
+// Run a subcommand with -h for its flags. The run command reads the same
+// settings, several seeds and an output directory from a JSON file; see
+// config.go for its fields. Configs are JSON only, YAML is not supported. The learners write per-episode metrics and value
+// table snapshots with -metrics dir, or into the output directory of a config.
+// Every command saves its table with -save; the learners resume from one
+// with -load and evaluate runs the policy it stands for.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+
+	"reinforcement-learning-playground/checkpoint"
+	"reinforcement-learning-playground/explorer"
+	"reinforcement-learning-playground/metrics"
+	"reinforcement-learning-playground/schedule"
+)
+
+const usage = `usage: rlp <command> [flags]
+
+commands:
+  dp eval   evaluate the uniform random policy by iterative policy evaluation
+  dp vi     value iteration
+  dp pi     policy iteration
+  mc        every-visit Monte Carlo control with an epsilon-greedy policy
+  td        TD(0) evaluation of the uniform random policy
+  sarsa     SARSA control with an epsilon-greedy policy
+  qlearn    Q-learning with an epsilon-greedy behaviour policy
+  run       run an experiment described by a JSON config file (YAML is not supported)
+  evaluate  run the policy of a saved checkpoint and report its returns
+`
+
+type options struct {
+	gamma    float64
+	theta    float64
+	alpha    float64
+	epsilon  float64
+	episodes int
+	maxSteps int
+	seed     int64
+	mapFile  string
+
+	metricsDir    string
+	snapshotEvery int
+	loadPath      string
+	savePath      string
+}
+
+var defaultOptions = options{
+	gamma:    0.9,
+	theta:    1e-8,
+	alpha:    0.1,
+	epsilon:  0.1,
+	episodes: 1000,
+	maxSteps: 1000,
+
+	snapshotEvery: 100,
+}
+
+func isLearning(name string) bool {
+	return name == "mc" || name == "td" || name == "sarsa" || name == "qlearn"
+}
+
+func isKnown(name string) bool {
+	return isLearning(name) || name == "dp eval" || name == "dp vi" || name == "dp pi"
+}
+
+func newFlagSet(name string, o *options) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Float64Var(&o.gamma, "gamma", defaultOptions.gamma, "discount factor")
+	fs.StringVar(&o.mapFile, "map", "", "map file of S, G, X, T and . blocks (default: the experiments' dungeon)")
+	if isLearning(name) || name == "evaluate" {
+		fs.IntVar(&o.episodes, "episodes", defaultOptions.episodes, "number of episodes")
+		fs.IntVar(&o.maxSteps, "max-steps", defaultOptions.maxSteps, "steps after which an episode is truncated")
+		fs.Int64Var(&o.seed, "seed", defaultOptions.seed, "random seed")
+	}
+	switch {
+	case isLearning(name):
+		fs.Float64Var(&o.alpha, "alpha", defaultOptions.alpha, "step size")
+		fs.Float64Var(&o.epsilon, "epsilon", defaultOptions.epsilon, "exploration rate of the epsilon-greedy policy")
+		fs.StringVar(&o.metricsDir, "metrics", "", "directory for per-episode metrics and value table snapshots in CSV, JSON Lines and a TensorBoard event file")
+		fs.IntVar(&o.snapshotEvery, "snapshot-every", defaultOptions.snapshotEvery, "episodes between value table snapshots, 0 for none")
+		fs.StringVar(&o.loadPath, "load", "", "checkpoint to resume training from")
+	case name == "evaluate":
+		fs.StringVar(&o.loadPath, "load", "", "checkpoint to evaluate")
+	default:
+		fs.Float64Var(&o.theta, "theta", defaultOptions.theta, "stop sweeping once no value changes by more than theta")
+	}
+	if name != "evaluate" {
+		fs.StringVar(&o.savePath, "save", "", "checkpoint file for the learned table, JSON if it ends in .json and binary otherwise")
+	}
+	return fs
+}
+
+func (o *options) validate() error {
+	if o.gamma < 0 || o.gamma > 1 {
+		return fmt.Errorf("gamma must be in [0, 1], got %v", o.gamma)
+	}
+	if o.epsilon < 0 || o.epsilon > 1 {
+		return fmt.Errorf("epsilon must be in [0, 1], got %v", o.epsilon)
+	}
+	if o.alpha < 0 {
+		return fmt.Errorf("alpha must not be negative, got %v", o.alpha)
+	}
+	if o.episodes < 0 || o.snapshotEvery < 0 {
+		return errors.New("episodes and snapshot-every must not be negative")
+	}
+	if o.maxSteps < 1 {
+		return fmt.Errorf("max-steps must be at least 1, got %d", o.maxSteps)
+	}
+	return nil
+}
+
+func run(args []string, w io.Writer) error {
+	if len(args) == 0 {
+		return errors.New("missing command")
+	}
+	name := args[0]
+	args = args[1:]
+	if name == "run" {
+		return runConfig(args, w)
+	}
+	if name == "dp" {
+		if len(args) == 0 {
+			return errors.New("dp needs one of eval, vi, pi")
+		}
+		name += " " + args[0]
+		args = args[1:]
+	}
+	if !isKnown(name) && name != "evaluate" {
+		return fmt.Errorf("unknown command %q", name)
+	}
+
+	// Options without a flag for this command keep their defaults.
+	o := defaultOptions
+	fs := newFlagSet(name, &o)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := o.validate(); err != nil {
+		return err
+	}
+	dungeon, err := loadMap(o.mapFile)
+	if err != nil {
+		return err
+	}
+	env, err := newEnv(dungeon)
+	if err != nil {
+		return err
+	}
+	if name == "evaluate" {
+		return evaluate(o, env, w)
+	}
+	return execute(name, o, env, newPolicy(env), w)
+}
+
+// execute runs one algorithm on env and writes its tables to w. policy is
+// the policy that dp eval and td evaluate. A loaded checkpoint replaces the
+// initial table of a learner, which then continues counting episodes from
+// it.
+func execute(name string, o options, env *Env, policy Policy, w io.Writer) (err error) {
+	fmt.Fprintln(w, "=========================================")
+	fmt.Fprintln(w, "Dungeon:")
+	printMap(w, env)
+	fmt.Fprintln(w, "=========================================")
+
+	rng := rand.New(rand.NewSource(o.seed))
+	agent := newAgent(o.gamma, o.alpha, explorer.NewEpsilonGreedy[[2]int](schedule.Constant(o.epsilon)), env, rng)
+	v := newV(env)
+	resumed := 0
+	if o.loadPath != "" {
+		c, err := loadCheckpoint(o.loadPath, env)
+		if err != nil {
+			return err
+		}
+		if name == "td" {
+			v, err = c.V()
+		} else {
+			agent.q, err = c.Q()
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", o.loadPath, err)
+		}
+		resumed = c.Episodes
+		fmt.Fprintf(w, "resumed from %s after %d episodes\n", o.loadPath, resumed)
+	}
+	hook := noHook
+	if o.metricsDir != "" && isLearning(name) {
+		logger, openErr := openMetrics(o.metricsDir, o.snapshotEvery)
+		if openErr != nil {
+			return openErr
+		}
+		defer func() {
+			if closeErr := logger.Close(); err == nil {
+				err = closeErr
+			}
+		}()
+		if name == "td" {
+			hook = logEpisodes(logger, func() []metrics.Value { return vValues(env, v) })
+		} else {
+			hook = logEpisodes(logger, func() []metrics.Value { return qValues(env, agent.q) })
+		}
+		hook = offsetEpisodes(hook, resumed)
+	}
+	var saved *checkpoint.Checkpoint
+	switch name {
+	case "dp eval":
+		sweeps := evaluatePolicy(env, policy, v, o.gamma, o.theta)
+		fmt.Fprintf(w, "v of the evaluated policy after %d sweeps:\n", sweeps)
+		printValues(w, env, v)
+		saved = checkpoint.FromV(v, actionNames(env))
+	case "dp vi":
+		sweeps := valueIteration(env, v, o.gamma, o.theta)
+		fmt.Fprintf(w, "v* after %d sweeps:\n", sweeps)
+		printValues(w, env, v)
+		fmt.Fprintln(w, "greedy policy:")
+		printPolicy(w, env, greedyPolicy(env, v, o.gamma))
+		saved = checkpoint.FromV(v, actionNames(env))
+	case "dp pi":
+		policy, iterations := policyIteration(env, v, o.gamma, o.theta)
+		fmt.Fprintf(w, "v* after %d policy improvements:\n", iterations)
+		printValues(w, env, v)
+		fmt.Fprintln(w, "policy:")
+		printPolicy(w, env, policy)
+		saved = checkpoint.FromPolicy(policy, actionNames(env))
+	case "td":
+		if err := tdEval(env, policy, v, o.gamma, o.alpha, o.episodes, o.maxSteps, rng, hook); err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "v of the evaluated policy after %d episodes:\n", resumed+o.episodes)
+		printValues(w, env, v)
+		saved = checkpoint.FromV(v, actionNames(env))
+	case "mc":
+		if err := monteCarlo(env, agent, o.episodes, o.maxSteps, hook); err != nil {
+			return err
+		}
+		printQ(w, env, agent.q)
+		saved = checkpoint.FromQ(agent.q, actionNames(env))
+	case "sarsa":
+		if err := sarsa(env, agent, o.episodes, o.maxSteps, hook); err != nil {
+			return err
+		}
+		printQ(w, env, agent.q)
+		saved = checkpoint.FromQ(agent.q, actionNames(env))
+	case "qlearn":
+		if err := qLearning(env, agent, o.episodes, o.maxSteps, hook); err != nil {
+			return err
+		}
+		printQ(w, env, agent.q)
+		saved = checkpoint.FromQ(agent.q, actionNames(env))
+	}
+	fmt.Fprintln(w, "=========================================")
+	if o.savePath != "" {
+		saved.Algorithm = name
+		if isLearning(name) {
+			saved.Episodes = resumed + o.episodes
+		}
+		if err := checkpoint.Save(o.savePath, saved); err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "saved %s to %s\n", saved.Kind, o.savePath)
+	}
+	return nil
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(os.Stderr, "rlp:", err)
+			fmt.Fprint(os.Stderr, usage)
+		}
+		os.Exit(2)
+	}
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestMaxStepsMustBePositive(t *testing.T) {
+	for _, name := range []string{"mc", "td", "sarsa", "qlearn"} {
+		for _, maxSteps := range []string{"0", "-1"} {
+			err := run([]string{name, "-episodes", "1", "-max-steps", maxSteps}, io.Discard)
+			if err == nil || !strings.Contains(err.Error(), "max-steps") {
+				t.Errorf("rlp %s -max-steps %s: error %v, want a max-steps error", name, maxSteps, err)
+			}
+		}
+		if err := run([]string{name, "-episodes", "1", "-max-steps", "1"}, io.Discard); err != nil {
+			t.Errorf("rlp %s -max-steps 1: %v", name, err)
+		}
+	}
+}
+
+func TestDPIgnoresMaxSteps(t *testing.T) {
+	if err := run([]string{"dp", "vi"}, io.Discard); err != nil {
+		t.Fatalf("rlp dp vi: %v", err)
+	}
+}
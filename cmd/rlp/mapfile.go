@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/marubontan/go-maze/maze"
+)
+
+// defaultMap is the dungeon of the experiments: start bottom left, goal top
+// right, one obstacle and a -1 trap below the goal.
+const defaultMap = `
+...G
+.X.T
+S...
+`
+
+// parseMap reads a rectangular grid with one character per block:
+//
+//	S  start
+//	G  goal, reward 1 and the episode ends
+//	X  obstacle
+//	T  trap, reward -1 on entering
+//	.  empty
+//
+// Blank lines and lines starting with # are skipped.
+func parseMap(r io.Reader) (*maze.Maze, error) {
+	rows := make([]string, 0)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if len(rows) > 0 && len(line) != len(rows[0]) {
+			return nil, fmt.Errorf("map row %d has %d blocks, expected %d", len(rows)+1, len(line), len(rows[0]))
+		}
+		rows = append(rows, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("map is empty")
+	}
+
+	dungeon := maze.NewMaze(len(rows), len(rows[0]))
+	starts, goals := 0, 0
+	for y, row := range rows {
+		for x, block := range row {
+			var err error
+			switch block {
+			case 'S':
+				starts++
+				err = dungeon.SetStart(x, y)
+			case 'G':
+				goals++
+				err = dungeon.SetGoal(x, y)
+			case 'X':
+				err = dungeon.SetObstacle(x, y)
+			case 'T':
+				dungeon.Blocks[y][x].Attributes = map[string]any{"reward": -1.0}
+			case '.':
+			default:
+				err = fmt.Errorf("unknown block %q at (%d, %d)", block, x, y)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	if starts != 1 || goals != 1 {
+		return nil, fmt.Errorf("map needs exactly one S and one G, found %d and %d", starts, goals)
+	}
+	return dungeon, nil
+}
+
+func loadMap(path string) (*maze.Maze, error) {
+	if path == "" {
+		return parseMap(strings.NewReader(defaultMap))
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return parseMap(f)
+}
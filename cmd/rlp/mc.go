@@ -0,0 +1,48 @@
+package main
+
+type Memory struct {
+	state  [2]int
+	action int
+	reward float64
+}
+
+// monteCarlo is every-visit constant-alpha Monte Carlo control. The return
+// of an episode cut off after maxSteps starts from the expected value of
+// the state it stopped at instead of zero. Its TD error is the gap between
+// the return and the estimate it updates.
+func monteCarlo(env *Env, agent *Agent, episodes int, maxSteps int, hook episodeHook) error {
+	for i := 0; i < episodes; i++ {
+		var stats episodeStats
+		memory := make([]Memory, 0)
+		state := env.start
+		g := 0.0
+		for t := 1; ; t++ {
+			action, err := agent.getAction(state)
+			if err != nil {
+				return err
+			}
+			nextState, reward, isGoal := env.Step(state, action)
+			memory = append(memory, Memory{state, action, reward})
+			if isGoal {
+				break
+			}
+			if t >= maxSteps {
+				g = agent.expectedQ(nextState)
+				break
+			}
+			state = nextState
+		}
+		for j := len(memory) - 1; j >= 0; j-- {
+			m := memory[j]
+			g = agent.gamma*g + m.reward
+			tdError := g - agent.q[m.state][m.action]
+			agent.q[m.state][m.action] += tdError * agent.alpha
+			stats.add(m.reward, tdError)
+		}
+		if err := hook(stats.episode(i, epsilon(agent.explorer))); err != nil {
+			return err
+		}
+		agent.explorer.Step()
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/marubontan/go-maze/maze"
+	"reinforcement-learning-playground/explorer"
+	"reinforcement-learning-playground/schedule"
+)
+
+// corridor is a two-block map: S at [0 0] and G at [1 0]. Left from S hits
+// the wall, Right reaches the goal.
+func corridor(t *testing.T) *Env {
+	t.Helper()
+	dungeon := maze.NewMaze(1, 2)
+	if err := dungeon.SetStart(0, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := dungeon.SetGoal(1, 0); err != nil {
+		t.Fatal(err)
+	}
+	env, err := newEnv(dungeon)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return env
+}
+
+// oneStepMonteCarlo plays one greedy episode cut off after a single step.
+// The goal's action values are nonzero so a return that wrongly bootstraps
+// from it shows up.
+func oneStepMonteCarlo(t *testing.T, q map[int]float64) *Agent {
+	t.Helper()
+	env := corridor(t)
+	strategy := explorer.NewEpsilonGreedy[[2]int](schedule.Constant(0))
+	agent := newAgent(0.9, 1.0, strategy, env, rand.New(rand.NewSource(0)))
+	for action, value := range q {
+		agent.q[env.start][action] = value
+	}
+	for action := range agent.q[env.goal] {
+		agent.q[env.goal][action] = 3.0
+	}
+	if err := monteCarlo(env, agent, 1, 1, noHook); err != nil {
+		t.Fatal(err)
+	}
+	return agent
+}
+
+func TestMonteCarloTruncatedReturnBootstraps(t *testing.T) {
+	left := slices.Index(actionNames(corridor(t)), "Left")
+	agent := oneStepMonteCarlo(t, map[int]float64{left: 0.5})
+	// The agent stays at S, whose greedy value was 0.5 when the episode was
+	// cut off.
+	want := 0.0 + 0.9*0.5
+	if got := agent.q[[2]int{0, 0}][left]; math.Abs(got-want) > 1e-9 {
+		t.Fatalf("q(S, Left) = %v, want %v", got, want)
+	}
+}
+
+func TestMonteCarloTerminatedReturnDoesNotBootstrap(t *testing.T) {
+	right := slices.Index(actionNames(corridor(t)), "Right")
+	agent := oneStepMonteCarlo(t, map[int]float64{right: 2.0})
+	if got := agent.q[[2]int{0, 0}][right]; math.Abs(got-1.0) > 1e-9 {
+		t.Fatalf("q(S, Right) = %v, want the goal reward 1", got)
+	}
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"math"
+	"os"
+
+	"reinforcement-learning-playground/explorer"
+	"reinforcement-learning-playground/metrics"
+	"reinforcement-learning-playground/tensorboard"
+)
+
+// An episodeHook receives the statistics of every finished episode.
+type episodeHook func(e metrics.Episode) error
+
+func noHook(e metrics.Episode) error {
+	return nil
+}
+
+type episodeStats struct {
+	reward  float64
+	steps   int
+	tdError float64
+}
+
+func (s *episodeStats) add(reward float64, tdError float64) {
+	s.reward += reward
+	s.steps++
+	s.tdError += math.Abs(tdError)
+}
+
+func (s *episodeStats) episode(i int, epsilon float64) metrics.Episode {
+	e := metrics.Episode{Episode: i + 1, Return: s.reward, Length: s.steps, Epsilon: epsilon}
+	if s.steps > 0 {
+		e.MeanTDError = s.tdError / float64(s.steps)
+	}
+	return e
+}
+
+// epsilon is the exploration rate of e, zero for explorers without one.
+func epsilon(e explorer.Explorer[[2]int]) float64 {
+	if e, ok := e.(interface{ Epsilon() float64 }); ok {
+		return e.Epsilon()
+	}
+	return 0
+}
+
+// tensorboardSink plots the episodes as scalars and the value table
+// snapshots as histograms.
+type tensorboardSink struct {
+	writer *tensorboard.Writer
+}
+
+func (t tensorboardSink) WriteEpisode(e metrics.Episode) error {
+	step := int64(e.Episode)
+	scalars := []struct {
+		tag   string
+		value float64
+	}{
+		{"episode/return", e.Return},
+		{"episode/length", float64(e.Length)},
+		{"episode/epsilon", e.Epsilon},
+		{"episode/mean_td_error", e.MeanTDError},
+	}
+	for _, s := range scalars {
+		if err := t.writer.Scalar(s.tag, step, s.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t tensorboardSink) WriteSnapshot(s metrics.Snapshot) error {
+	values := make([]float64, len(s.Values))
+	for i, v := range s.Values {
+		values[i] = v.Value
+	}
+	if err := t.writer.Histogram("values", int64(s.Episode), values, 30); err != nil {
+		return err
+	}
+	return t.writer.Flush()
+}
+
+func (t tensorboardSink) Close() error {
+	return t.writer.Close()
+}
+
+// openMetrics logs into dir as CSV, JSON Lines and a TensorBoard event file.
+func openMetrics(dir string, snapshotEvery int) (*metrics.Logger, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	c, err := metrics.NewCSV(dir)
+	if err != nil {
+		return nil, err
+	}
+	j, err := metrics.NewJSONL(dir)
+	if err != nil {
+		c.Close()
+		return nil, err
+	}
+	t, err := tensorboard.NewWriter(dir)
+	if err != nil {
+		c.Close()
+		j.Close()
+		return nil, err
+	}
+	return metrics.NewLogger(snapshotEvery, c, j, tensorboardSink{t}), nil
+}
+
+// logEpisodes writes every episode to logger along with a snapshot of the
+// table returned by values whenever one is due.
+func logEpisodes(logger *metrics.Logger, values func() []metrics.Value) episodeHook {
+	return func(e metrics.Episode) error {
+		if err := logger.Episode(e); err != nil {
+			return err
+		}
+		if logger.SnapshotDue(e.Episode) {
+			return logger.Snapshot(e.Episode, values())
+		}
+		return nil
+	}
+}
+
+func vValues(env *Env, v map[[2]int]float64) []metrics.Value {
+	values := make([]metrics.Value, 0)
+	for _, state := range env.States() {
+		values = append(values, metrics.Value{State: state, Value: v[state]})
+	}
+	return values
+}
+
+func qValues(env *Env, q map[[2]int]map[int]float64) []metrics.Value {
+	values := make([]metrics.Value, 0)
+	for _, state := range env.States() {
+		for _, action := range env.Actions() {
+			values = append(values, metrics.Value{State: state, Action: env.world.ActionName(action), Value: q[state][action]})
+		}
+	}
+	return values
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"reinforcement-learning-playground/explorer"
+)
+
+// printMap writes the blocks back in the map file notation.
+func printMap(w io.Writer, env *Env) {
+	for y, row := range env.world.Maze.Blocks {
+		line := make([]byte, len(row))
+		for x := range row {
+			state := [2]int{x, y}
+			switch {
+			case !env.world.Maze.IsAvailable(x, y):
+				line[x] = 'X'
+			case state == env.start:
+				line[x] = 'S'
+			case env.IsGoal(state):
+				line[x] = 'G'
+			case env.Reward(state) < 0:
+				line[x] = 'T'
+			default:
+				line[x] = '.'
+			}
+		}
+		fmt.Fprintln(w, string(line))
+	}
+}
+
+func printValues(w io.Writer, env *Env, v map[[2]int]float64) {
+	for y, row := range env.world.Maze.Blocks {
+		cells := make([]string, len(row))
+		for x := range row {
+			if !env.world.Maze.IsAvailable(x, y) {
+				cells[x] = fmt.Sprintf("%7s", "X")
+			} else {
+				cells[x] = fmt.Sprintf("%7.3f", v[[2]int{x, y}])
+			}
+		}
+		fmt.Fprintln(w, strings.Join(cells, " "))
+	}
+}
+
+// printPolicy shows the initials of the most probable actions of every
+// block, so ties print as several letters.
+func printPolicy(w io.Writer, env *Env, policy Policy) {
+	for y, row := range env.world.Maze.Blocks {
+		cells := make([]string, len(row))
+		for x := range row {
+			state := [2]int{x, y}
+			switch {
+			case !env.world.Maze.IsAvailable(x, y):
+				cells[x] = "X"
+			case env.IsGoal(state):
+				cells[x] = "G"
+			default:
+				for _, action := range explorer.ArgmaxAll(policy[state]) {
+					cells[x] += env.world.ActionName(action)[:1]
+				}
+			}
+			cells[x] = fmt.Sprintf("%7s", cells[x])
+		}
+		fmt.Fprintln(w, strings.Join(cells, " "))
+	}
+}
+
+func printQ(w io.Writer, env *Env, q map[[2]int]map[int]float64) {
+	v := make(map[[2]int]float64)
+	for state, stateQ := range q {
+		v[state] = stateQ[explorer.ArgmaxAll(stateQ)[0]]
+	}
+	fmt.Fprintln(w, "max_a q(s, a):")
+	printValues(w, env, v)
+	fmt.Fprintln(w, "greedy policy:")
+	printPolicy(w, env, qPolicy(q))
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"errors"
+	"math/rand"
+
+	"reinforcement-learning-playground/explorer"
+)
+
+type Agent struct {
+	gamma    float64
+	alpha    float64
+	explorer explorer.Explorer[[2]int]
+	q        map[[2]int]map[int]float64
+	actions  []int
+	rng      *rand.Rand
+}
+
+func newAgent(gamma float64, alpha float64, explorer explorer.Explorer[[2]int], env *Env, rng *rand.Rand) *Agent {
+	q := make(map[[2]int]map[int]float64)
+	for _, state := range env.States() {
+		q[state] = make(map[int]float64)
+		for _, action := range env.Actions() {
+			q[state][action] = explorer.InitialQ()
+		}
+	}
+	return &Agent{
+		gamma:    gamma,
+		alpha:    alpha,
+		explorer: explorer,
+		q:        q,
+		actions:  env.Actions(),
+		rng:      rng,
+	}
+}
+
+func sampleAction(statePolicy map[int]float64, actions []int, rng *rand.Rand) (int, error) {
+	cumProb := 0.0
+	sample := rng.Float64()
+	for _, action := range actions {
+		cumProb += statePolicy[action]
+		if sample < cumProb {
+			return action, nil
+		}
+	}
+	return -1, errors.New("action not found")
+}
+
+func (a *Agent) getAction(state [2]int) (int, error) {
+	action, err := sampleAction(a.explorer.Probs(state, a.q[state]), a.actions, a.rng)
+	if err == nil {
+		a.explorer.Observe(state, action)
+	}
+	return action, err
+}
+
+func (a *Agent) maxQ(state [2]int) float64 {
+	return a.q[state][explorer.ArgmaxAll(a.q[state])[0]]
+}
+
+// expectedQ is the value of state under the current behaviour policy.
+func (a *Agent) expectedQ(state [2]int) float64 {
+	v := 0.0
+	for action, prob := range a.explorer.Probs(state, a.q[state]) {
+		v += prob * a.q[state][action]
+	}
+	return v
+}
+
+// tdEval estimates v of policy with TD(0). Episodes start at S and are cut
+// off after maxSteps; only reaching the goal drops the bootstrap term.
+func tdEval(env *Env, policy Policy, v map[[2]int]float64, gamma float64, alpha float64, episodes int, maxSteps int, rng *rand.Rand, hook episodeHook) error {
+	for i := 0; i < episodes; i++ {
+		var stats episodeStats
+		state := env.start
+		for t := 1; ; t++ {
+			action, err := sampleAction(policy[state], env.Actions(), rng)
+			if err != nil {
+				return err
+			}
+			nextState, reward, isGoal := env.Step(state, action)
+			nextV := 0.0
+			if !isGoal {
+				nextV = v[nextState]
+			}
+			tdError := reward + gamma*nextV - v[state]
+			v[state] += tdError * alpha
+			stats.add(reward, tdError)
+			if isGoal || t >= maxSteps {
+				break
+			}
+			state = nextState
+		}
+		if err := hook(stats.episode(i, 0)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func sarsa(env *Env, agent *Agent, episodes int, maxSteps int, hook episodeHook) error {
+	for i := 0; i < episodes; i++ {
+		var stats episodeStats
+		state := env.start
+		action, err := agent.getAction(state)
+		if err != nil {
+			return err
+		}
+		for t := 1; ; t++ {
+			nextState, reward, isGoal := env.Step(state, action)
+			if isGoal {
+				tdError := reward - agent.q[state][action]
+				agent.q[state][action] += tdError * agent.alpha
+				stats.add(reward, tdError)
+				break
+			}
+			nextAction, err := agent.getAction(nextState)
+			if err != nil {
+				return err
+			}
+			tdError := reward + agent.gamma*agent.q[nextState][nextAction] - agent.q[state][action]
+			agent.q[state][action] += tdError * agent.alpha
+			stats.add(reward, tdError)
+			if t >= maxSteps {
+				break
+			}
+			state = nextState
+			action = nextAction
+		}
+		if err := hook(stats.episode(i, epsilon(agent.explorer))); err != nil {
+			return err
+		}
+		agent.explorer.Step()
+	}
+	return nil
+}
+
+func qLearning(env *Env, agent *Agent, episodes int, maxSteps int, hook episodeHook) error {
+	for i := 0; i < episodes; i++ {
+		var stats episodeStats
+		state := env.start
+		for t := 1; ; t++ {
+			action, err := agent.getAction(state)
+			if err != nil {
+				return err
+			}
+			nextState, reward, isGoal := env.Step(state, action)
+			target := reward
+			if !isGoal {
+				target += agent.gamma * agent.maxQ(nextState)
+			}
+			tdError := target - agent.q[state][action]
+			agent.q[state][action] += tdError * agent.alpha
+			stats.add(reward, tdError)
+			if isGoal || t >= maxSteps {
+				break
+			}
+			state = nextState
+		}
+		if err := hook(stats.episode(i, epsilon(agent.explorer))); err != nil {
+			return err
+		}
+		agent.explorer.Step()
+	}
+	return nil
+}
@@ -0,0 +1,246 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/marubontan/go-maze/maze"
+	"reinforcement-learning-playground/classiccontrol"
+	"reinforcement-learning-playground/gridworld"
+	"reinforcement-learning-playground/nn"
+)
+
+func composeDungen() *maze.Maze {
+	dungeon := maze.NewMaze(3, 4)
+	var err error
+	err = dungeon.SetStart(0, 2)
+	if err != nil {
+		panic(err)
+	}
+	err = dungeon.SetGoal(3, 0)
+	if err != nil {
+		panic(err)
+	}
+	err = dungeon.SetObstacle(1, 1)
+	if err != nil {
+		panic(err)
+	}
+	return dungeon
+}
+
+func printDungenConf() {
+	fmt.Println("Dungeon Configuration:")
+	fmt.Println("S: Start Position")
+	fmt.Println("X: Obstacle")
+	fmt.Println(("G: Goal with Reward 1"))
+}
+
+func getReward(state [2]int, goalX, goalY int) float64 {
+	if state[0] == goalX && state[1] == goalY {
+		return 1.0
+	}
+	if state[0] == 3 && state[1] == 1 {
+		return -1.0
+	}
+	return 0
+}
+
+type Agent struct {
+	gamma       float64
+	n           int
+	valueCoef   float64
+	entropyCoef float64
+	actor       nn.Sequential
+	critic      nn.Sequential
+	params      []*nn.Tensor
+	optimizer   nn.Optimizer
+	memory      []Memory
+	actions     []int
+	rng         *rand.Rand
+}
+
+type Memory struct {
+	observation []float64
+	action      int
+	reward      float64
+}
+
+func newAgent(gamma float64, n int, lr float64, valueCoef float64, entropyCoef float64, sizes []int, actions []int, rng *rand.Rand) *Agent {
+	actorSizes := append(append([]int{}, sizes...), len(actions))
+	criticSizes := append(append([]int{}, sizes...), 1)
+	actor := nn.NewMLP(actorSizes, nn.Tanh, rng)
+	critic := nn.NewMLP(criticSizes, nn.Tanh, rng)
+	params := append(actor.Params(), critic.Params()...)
+	return &Agent{
+		gamma:       gamma,
+		n:           n,
+		valueCoef:   valueCoef,
+		entropyCoef: entropyCoef,
+		actor:       actor,
+		critic:      critic,
+		params:      params,
+		optimizer:   nn.NewAdam(params, lr),
+		memory:      make([]Memory, 0, n),
+		actions:     actions,
+		rng:         rng,
+	}
+}
+
+func (a *Agent) probs(observation []float64) []float64 {
+	logits := a.actor.Forward(nn.New(1, len(observation), observation))
+	return nn.Exp(nn.LogSoftmax(logits)).Data
+}
+
+func (a *Agent) value(observation []float64) float64 {
+	return a.critic.Forward(nn.New(1, len(observation), observation)).Item()
+}
+
+func (a *Agent) getAction(observation []float64) (int, error) {
+	statePolicy := a.probs(observation)
+	cumProb := 0.0
+	sample := a.rng.Float64()
+	for _, action := range a.actions {
+		cumProb += statePolicy[action]
+		if sample < cumProb {
+			return action, nil
+		}
+	}
+	return -1, errors.New("action not found")
+}
+
+// update trains at the end of every rollout. A rollout cut short by the time
+// limit still bootstraps from the value of the observation it stopped at.
+func (a *Agent) update(observation []float64, action int, reward float64, nextObservation []float64, isGoal bool, truncated bool) {
+	a.memory = append(a.memory, Memory{observation, action, reward})
+	if isGoal || truncated || len(a.memory) == a.n {
+		a.learn(nextObservation, isGoal)
+		a.memory = a.memory[:0]
+	}
+}
+
+// learn bootstraps every step of the rollout from the value of the state
+// after its last step, so step t uses an (n-t)-step return, and takes one
+// gradient step on the policy, value and entropy terms together.
+func (a *Agent) learn(nextObservation []float64, isGoal bool) {
+	g := 0.0
+	if !isGoal {
+		g = a.value(nextObservation)
+	}
+	size := len(a.memory)
+	observations := make([][]float64, size)
+	actions := make([]int, size)
+	returns := nn.Zeros(size, 1)
+	for i := size - 1; i >= 0; i-- {
+		memory := a.memory[i]
+		g = a.gamma*g + memory.reward
+		observations[i] = memory.observation
+		actions[i] = memory.action
+		returns.Data[i] = g
+	}
+	states := nn.FromRows(observations)
+	values := a.critic.Forward(states)
+	advantages := nn.Sub(returns, values).Detach()
+	logProbs := nn.LogSoftmax(a.actor.Forward(states))
+	policyLoss := nn.Scale(nn.Mean(nn.Mul(nn.Gather(logProbs, actions), advantages)), -1.0)
+	valueLoss := nn.MSE(values, returns)
+	negEntropy := nn.Scale(nn.Sum(nn.Mul(nn.Exp(logProbs), logProbs)), 1.0/float64(size))
+	loss := nn.Add(nn.Add(policyLoss, nn.Scale(valueLoss, a.valueCoef)), nn.Scale(negEntropy, a.entropyCoef))
+	a.optimizer.ZeroGrad()
+	loss.Backward()
+	nn.ClipGradNorm(a.params, 0.5)
+	a.optimizer.Step()
+}
+
+func iterSteps(steps int, agent *Agent, env classiccontrol.Env) []float64 {
+	returns := make([]float64, 0)
+	observation := env.Reset()
+	g := 0.0
+	for i := 0; i < steps; i++ {
+		action, err := agent.getAction(observation)
+		if err != nil {
+			panic(err)
+		}
+		nextObservation, reward, isGoal, truncated := env.Step(action)
+		agent.update(observation, action, reward, nextObservation, isGoal, truncated)
+		g += reward
+		if isGoal || truncated {
+			returns = append(returns, g)
+			observation = env.Reset()
+			g = 0.0
+		} else {
+			observation = nextObservation
+		}
+	}
+	return returns
+}
+
+func evaluate(episodes int, agent *Agent, env classiccontrol.Env) float64 {
+	sum := 0.0
+	for i := 0; i < episodes; i++ {
+		observation := env.Reset()
+		for {
+			statePolicy := agent.probs(observation)
+			action := agent.actions[0]
+			for _, candidate := range agent.actions {
+				if statePolicy[candidate] > statePolicy[action] {
+					action = candidate
+				}
+			}
+			nextObservation, reward, isGoal, truncated := env.Step(action)
+			sum += reward
+			if isGoal || truncated {
+				break
+			}
+			observation = nextObservation
+		}
+	}
+	return sum / float64(episodes)
+}
+
+func printReturns(returns []float64, window int) {
+	for i := 0; i+window <= len(returns); i += window {
+		sum := 0.0
+		for _, g := range returns[i : i+window] {
+			sum += g
+		}
+		fmt.Printf("episodes %4d-%4d: %.1f\n", i+1, i+window, sum/float64(window))
+	}
+}
+
+func main() {
+	dungeon := composeDungen()
+	fmt.Println("=========================================")
+	fmt.Println("Dungeon:")
+	dungeon.Print()
+	fmt.Println("=========================================")
+	printDungenConf()
+	fmt.Println("=========================================")
+	world := gridworld.New(dungeon, gridworld.FourWay)
+	rng := rand.New(rand.NewSource(0))
+	gridEnv := gridworld.NewEnv(world, getReward, [2]int{0, 0}, 100)
+	gridAgent := newAgent(0.9, 5, 1e-2, 0.5, 0.01, []int{gridEnv.Dim(), 32}, world.Actions(), rng)
+	returns := iterSteps(5000, gridAgent, gridEnv)
+	fmt.Println("Gridworld mean return per 250 episodes:")
+	printReturns(returns, 250)
+	v := make(map[[2]int]float64)
+	for hI, hBlocks := range dungeon.Blocks {
+		for wI := range hBlocks {
+			state := [2]int{wI, hI}
+			v[state] = gridAgent.value(gridEnv.Observation(state))
+		}
+	}
+	fmt.Println(v)
+	fmt.Println("=========================================")
+
+	start := time.Now()
+	cartPole := classiccontrol.NewCartPole(rng)
+	cartAgent := newAgent(0.99, 32, 1e-3, 0.5, 0.01, []int{4, 64}, cartPole.Actions(), rng)
+	returns = iterSteps(200000, cartAgent, cartPole)
+	fmt.Println("CartPole mean return per 100 episodes:")
+	printReturns(returns, 100)
+	fmt.Printf("trained for 200000 steps in %s\n", time.Since(start).Round(time.Second))
+	fmt.Printf("greedy mean return over 10 episodes: %.1f\n", evaluate(10, cartAgent, cartPole))
+	fmt.Println("=========================================")
+}
@@ -0,0 +1,246 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"reinforcement-learning-playground/classiccontrol"
+	"reinforcement-learning-playground/nn"
+	"reinforcement-learning-playground/vecenv"
+)
+
+type Agent struct {
+	gamma       float64
+	n           int
+	valueCoef   float64
+	entropyCoef float64
+	actor       nn.Sequential
+	critic      nn.Sequential
+	params      []*nn.Tensor
+	optimizer   nn.Optimizer
+	memory      []Memory
+	actions     []int
+	rng         *rand.Rand
+}
+
+// Memory holds one batched step, one entry per environment copy.
+// nextValues is only set for copies whose episode was truncated.
+type Memory struct {
+	observations [][]float64
+	actions      []int
+	rewards      []float64
+	isGoals      []bool
+	truncated    []bool
+	nextValues   []float64
+}
+
+func newAgent(gamma float64, n int, lr float64, valueCoef float64, entropyCoef float64, sizes []int, actions []int, rng *rand.Rand) *Agent {
+	actorSizes := append(append([]int{}, sizes...), len(actions))
+	criticSizes := append(append([]int{}, sizes...), 1)
+	actor := nn.NewMLP(actorSizes, nn.Tanh, rng)
+	critic := nn.NewMLP(criticSizes, nn.Tanh, rng)
+	params := append(actor.Params(), critic.Params()...)
+	return &Agent{
+		gamma:       gamma,
+		n:           n,
+		valueCoef:   valueCoef,
+		entropyCoef: entropyCoef,
+		actor:       actor,
+		critic:      critic,
+		params:      params,
+		optimizer:   nn.NewAdam(params, lr),
+		memory:      make([]Memory, 0, n),
+		actions:     actions,
+		rng:         rng,
+	}
+}
+
+func (a *Agent) getActions(observations [][]float64) ([]int, error) {
+	probs := nn.Exp(nn.LogSoftmax(a.actor.Forward(nn.FromRows(observations))))
+	actions := make([]int, len(observations))
+	for i := range observations {
+		statePolicy := probs.Row(i)
+		actions[i] = -1
+		cumProb := 0.0
+		sample := a.rng.Float64()
+		for _, action := range a.actions {
+			cumProb += statePolicy[action]
+			if sample < cumProb {
+				actions[i] = action
+				break
+			}
+		}
+		if actions[i] == -1 {
+			return nil, errors.New("action not found")
+		}
+	}
+	return actions, nil
+}
+
+func (a *Agent) update(observations [][]float64, actions []int, results []vecenv.Result) {
+	memory := Memory{
+		observations: observations,
+		actions:      actions,
+		rewards:      make([]float64, len(results)),
+		isGoals:      make([]bool, len(results)),
+		truncated:    make([]bool, len(results)),
+		nextValues:   make([]float64, len(results)),
+	}
+	nextObservations := make([][]float64, len(results))
+	for i, result := range results {
+		memory.rewards[i] = result.Reward
+		memory.isGoals[i] = result.IsGoal
+		memory.truncated[i] = result.Truncated
+		if result.Truncated {
+			memory.nextValues[i] = a.critic.Forward(nn.New(1, len(result.FinalObservation), result.FinalObservation)).Item()
+		}
+		nextObservations[i] = result.Observation
+	}
+	a.memory = append(a.memory, memory)
+	if len(a.memory) == a.n {
+		a.learn(nextObservations)
+		a.memory = a.memory[:0]
+	}
+}
+
+// learn computes the n-step return of every copy backwards from the value
+// of its latest observation, restarting from zero where an episode
+// terminated and from the stored value where it was truncated, and takes
+// one gradient step on the whole n x copies batch.
+func (a *Agent) learn(nextObservations [][]float64) {
+	copies := len(nextObservations)
+	g := a.critic.Forward(nn.FromRows(nextObservations)).Data
+	size := len(a.memory) * copies
+	observations := make([][]float64, size)
+	actions := make([]int, size)
+	returns := nn.Zeros(size, 1)
+	for t := len(a.memory) - 1; t >= 0; t-- {
+		memory := a.memory[t]
+		for e := 0; e < copies; e++ {
+			if memory.isGoals[e] {
+				g[e] = 0.0
+			} else if memory.truncated[e] {
+				g[e] = memory.nextValues[e]
+			}
+			g[e] = a.gamma*g[e] + memory.rewards[e]
+			observations[t*copies+e] = memory.observations[e]
+			actions[t*copies+e] = memory.actions[e]
+			returns.Data[t*copies+e] = g[e]
+		}
+	}
+	states := nn.FromRows(observations)
+	values := a.critic.Forward(states)
+	advantages := nn.Sub(returns, values).Detach()
+	logProbs := nn.LogSoftmax(a.actor.Forward(states))
+	policyLoss := nn.Scale(nn.Mean(nn.Mul(nn.Gather(logProbs, actions), advantages)), -1.0)
+	valueLoss := nn.MSE(values, returns)
+	negEntropy := nn.Scale(nn.Sum(nn.Mul(nn.Exp(logProbs), logProbs)), 1.0/float64(size))
+	loss := nn.Add(nn.Add(policyLoss, nn.Scale(valueLoss, a.valueCoef)), nn.Scale(negEntropy, a.entropyCoef))
+	a.optimizer.ZeroGrad()
+	loss.Backward()
+	nn.ClipGradNorm(a.params, 0.5)
+	a.optimizer.Step()
+}
+
+func iterSteps(steps int, agent *Agent, envs *vecenv.VecEnv) []float64 {
+	returns := make([]float64, 0)
+	observations, err := envs.Reset()
+	if err != nil {
+		panic(err)
+	}
+	g := make([]float64, envs.Len())
+	for i := 0; i < steps; i++ {
+		actions, err := agent.getActions(observations)
+		if err != nil {
+			panic(err)
+		}
+		results, err := envs.Step(actions)
+		if err != nil {
+			panic(err)
+		}
+		agent.update(observations, actions, results)
+		observations = make([][]float64, len(results))
+		for e, result := range results {
+			g[e] += result.Reward
+			if result.IsGoal || result.Truncated {
+				returns = append(returns, g[e])
+				g[e] = 0.0
+			}
+			observations[e] = result.Observation
+		}
+	}
+	return returns
+}
+
+func printReturns(returns []float64, window int) {
+	for i := 0; i+window <= len(returns); i += window {
+		sum := 0.0
+		for _, g := range returns[i : i+window] {
+			sum += g
+		}
+		fmt.Printf("episodes %4d-%4d: %.1f\n", i+1, i+window, sum/float64(window))
+	}
+}
+
+// compareModes steps the copies with random actions, once waiting for every
+// batch and once overlapping the next batch of action sampling with the
+// copies stepping.
+func compareModes(steps int, envs *vecenv.VecEnv, rng *rand.Rand) {
+	randomActions := func() []int {
+		actions := make([]int, envs.Len())
+		for i := range actions {
+			actions[i] = envs.Actions()[rng.Intn(len(envs.Actions()))]
+		}
+		return actions
+	}
+
+	if _, err := envs.Reset(); err != nil {
+		panic(err)
+	}
+	start := time.Now()
+	for i := 0; i < steps; i++ {
+		if _, err := envs.Step(randomActions()); err != nil {
+			panic(err)
+		}
+	}
+	fmt.Printf("sync:  %d batched steps in %s\n", steps, time.Since(start).Round(time.Millisecond))
+
+	if _, err := envs.Reset(); err != nil {
+		panic(err)
+	}
+	start = time.Now()
+	actions := randomActions()
+	for i := 0; i < steps; i++ {
+		if err := envs.StepAsync(actions); err != nil {
+			panic(err)
+		}
+		actions = randomActions()
+		if _, err := envs.StepWait(); err != nil {
+			panic(err)
+		}
+	}
+	fmt.Printf("async: %d batched steps in %s\n", steps, time.Since(start).Round(time.Millisecond))
+}
+
+func main() {
+	copies := 8
+	rng := rand.New(rand.NewSource(0))
+	envs := vecenv.New(copies, func(i int) classiccontrol.Env {
+		return classiccontrol.NewCartPole(rand.New(rand.NewSource(int64(i))))
+	})
+	defer envs.Close()
+
+	fmt.Println("=========================================")
+	compareModes(10000, envs, rng)
+	fmt.Println("=========================================")
+
+	start := time.Now()
+	agent := newAgent(0.99, 16, 1e-3, 0.5, 0.01, []int{4, 64}, envs.Actions(), rng)
+	returns := iterSteps(50000, agent, envs)
+	fmt.Printf("CartPole x%d mean return per 100 episodes:\n", copies)
+	printReturns(returns, 100)
+	fmt.Printf("trained for %d batched steps in %s\n", 50000, time.Since(start).Round(time.Second))
+	fmt.Println("=========================================")
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+
+	"github.com/marubontan/go-maze/maze"
+	"reinforcement-learning-playground/approx"
+	"reinforcement-learning-playground/gridworld"
+)
+
+func composeDungen() *maze.Maze {
+	dungeon := maze.NewMaze(3, 4)
+	var err error
+	err = dungeon.SetStart(0, 2)
+	if err != nil {
+		panic(err)
+	}
+	err = dungeon.SetGoal(3, 0)
+	if err != nil {
+		panic(err)
+	}
+	err = dungeon.SetObstacle(1, 1)
+	if err != nil {
+		panic(err)
+	}
+	return dungeon
+}
+
+func printDungenConf() {
+	fmt.Println("Dungeon Configuration:")
+	fmt.Println("S: Start Position")
+	fmt.Println("X: Obstacle")
+	fmt.Println(("G: Goal with Reward 1"))
+}
+
+type State [][2]int
+
+func getStates(m *maze.Maze) State {
+	blockIndices := make(State, 0)
+	for hI, hBlocks := range m.Blocks {
+		for wI := range hBlocks {
+			blockIndices = append(blockIndices, [2]int{wI, hI})
+		}
+	}
+	return blockIndices
+}
+
+type Agent struct {
+	gamma      float64
+	alphaTheta float64
+	alphaW     float64
+	policy     *approx.SoftmaxPolicy
+	v          *approx.LinearV
+	discount   float64
+	actions    []int
+	rng        *rand.Rand
+}
+
+func newAgent(gamma float64, alphaTheta float64, alphaW float64, policy *approx.SoftmaxPolicy, v *approx.LinearV, actions []int, rng *rand.Rand) *Agent {
+	return &Agent{
+		gamma:      gamma,
+		alphaTheta: alphaTheta,
+		alphaW:     alphaW,
+		policy:     policy,
+		v:          v,
+		discount:   1.0,
+		actions:    actions,
+		rng:        rng,
+	}
+}
+
+func (a *Agent) getAction(state [2]int) (int, error) {
+	statePolicy := a.policy.Probs(state)
+	cumProb := 0.0
+	sample := a.rng.Float64()
+	for _, action := range a.actions {
+		cumProb += statePolicy[action]
+		if sample < cumProb {
+			return action, nil
+		}
+	}
+	return -1, errors.New("action not found")
+}
+
+// eval is the TD(0) critic of td_eval; its error also serves as the
+// advantage estimate for the actor, scaled by the running gamma^t.
+func (a *Agent) eval(state [2]int, action int, reward float64, nextState [2]int, isGoal bool) {
+	var nextV float64
+	if isGoal {
+		nextV = 0.0
+	} else {
+		nextV = a.v.Value(nextState)
+	}
+	delta := reward + a.gamma*nextV - a.v.Value(state)
+	a.v.Update(state, a.alphaW, delta)
+	a.policy.Update(state, action, a.alphaTheta, a.discount*delta)
+	a.discount *= a.gamma
+}
+
+func (a *Agent) step(state [2]int, action int, t int, maxSteps int, w *gridworld.World) ([2]int, float64, bool, bool) {
+	goalX, goalY, err := w.Maze.GetGoal()
+	if err != nil {
+		panic(err)
+	}
+	nextState := w.NextState(state, action)
+	reward := getReward(nextState, goalX, goalY)
+	isGoal := nextState[0] == goalX && nextState[1] == goalY
+	truncated := !isGoal && t >= maxSteps
+	return nextState, reward, isGoal, truncated
+}
+
+func (a *Agent) reset() {
+	a.discount = 1.0
+}
+
+func getReward(state [2]int, goalX, goalY int) float64 {
+	if state[0] == goalX && state[1] == goalY {
+		return 1.0
+	}
+	if state[0] == 3 && state[1] == 1 {
+		return -1.0
+	}
+	return 0
+}
+
+// iterEpisodes cuts every episode off after maxSteps. The critic keeps
+// v(nextState) in the last TD error of a truncated episode.
+func iterEpisodes(episodes int, maxSteps int, agent *Agent, world *gridworld.World) []int {
+	states := getStates(world.Maze)
+	lengths := make([]int, 0, episodes)
+	for i := 0; i < episodes; i++ {
+		state := states[0]
+		agent.reset()
+		length := 0
+		for t := 1; ; t++ {
+			action, err := agent.getAction(state)
+			if err != nil {
+				panic(err)
+			}
+			nextState, reward, isGoal, truncated := agent.step(state, action, t, maxSteps, world)
+			agent.eval(state, action, reward, nextState, isGoal)
+			length++
+			if isGoal || truncated {
+				break
+			}
+			state = nextState
+		}
+		lengths = append(lengths, length)
+	}
+	return lengths
+}
+
+func main() {
+	dungeon := composeDungen()
+	fmt.Println("=========================================")
+	fmt.Println("Dungeon:")
+	dungeon.Print()
+	fmt.Println("=========================================")
+	printDungenConf()
+	fmt.Println("=========================================")
+	world := gridworld.New(dungeon, gridworld.FourWay)
+	oneHot := approx.NewOneHot(dungeon.Width, dungeon.Height)
+	rbf := approx.NewRadialBasis(dungeon.Width, dungeon.Height, 4, 3, 0.3)
+	configs := []struct {
+		name     string
+		features approx.Features
+	}{
+		{"tabular", oneHot},
+		{"linear rbf", rbf},
+	}
+	var agent *Agent
+	for _, config := range configs {
+		rng := rand.New(rand.NewSource(0))
+		policy := approx.NewSoftmaxPolicy(config.features, world.Actions())
+		agent = newAgent(0.9, 0.1, 0.1, policy, approx.NewLinearV(config.features), world.Actions(), rng)
+		lengths := iterEpisodes(2000, 100, agent, world)
+		fmt.Printf("%-12s mean episode length", config.name)
+		for i := 0; i < len(lengths); i += 500 {
+			sum := 0
+			for _, length := range lengths[i : i+500] {
+				sum += length
+			}
+			fmt.Printf(" %6.2f", float64(sum)/500.0)
+		}
+		fmt.Println()
+	}
+	fmt.Println("=========================================")
+	v := make(map[[2]int]float64)
+	probs := make(map[[2]int]map[int]float64)
+	for _, state := range getStates(dungeon) {
+		v[state] = agent.v.Value(state)
+		probs[state] = agent.policy.Probs(state)
+	}
+	fmt.Println(v)
+	fmt.Println(probs)
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+
+	"reinforcement-learning-playground/bandit"
+	"reinforcement-learning-playground/explorer"
+	"reinforcement-learning-playground/schedule"
+)
+
+type namedAgent struct {
+	name     string
+	newAgent func(arms int, rng *rand.Rand) bandit.Agent
+}
+
+func printResult(name string, result bandit.Result) {
+	steps := len(result.AverageReward)
+	window := steps / 10
+	reward := 0.0
+	optimal := 0.0
+	for t := steps - window; t < steps; t++ {
+		reward += result.AverageReward[t]
+		optimal += result.OptimalAction[t]
+	}
+	fmt.Printf("%-20s reward %6.3f  optimal %5.1f%%\n", name, reward/float64(window), 100*optimal/float64(window))
+}
+
+func main() {
+	runs := 500
+	steps := 1000
+	agents := []namedAgent{
+		{"epsilon-greedy 0.1", func(arms int, rng *rand.Rand) bandit.Agent {
+			return bandit.NewEpsilonGreedyAgent(arms, 0.1, 0.0, rng)
+		}},
+		{"optimistic 5", func(arms int, rng *rand.Rand) bandit.Agent {
+			greedy := explorer.NewEpsilonGreedy[explorer.NoState](schedule.Constant(0.0))
+			return bandit.NewValueAgent(arms, explorer.NewOptimistic(greedy, 5.0), 0.1, rng)
+		}},
+		{"UCB c=2", func(arms int, rng *rand.Rand) bandit.Agent {
+			return bandit.NewUCBAgent(arms, 2.0, 0.0, rng)
+		}},
+		{"gradient 0.1", func(arms int, rng *rand.Rand) bandit.Agent {
+			return bandit.NewGradientAgent(arms, 0.1, rng)
+		}},
+		{"thompson gaussian", func(arms int, rng *rand.Rand) bandit.Agent {
+			return bandit.NewGaussianThompsonAgent(arms, rng)
+		}},
+	}
+	betaThompson := namedAgent{"thompson beta", func(arms int, rng *rand.Rand) bandit.Agent {
+		return bandit.NewBetaThompsonAgent(arms, rng)
+	}}
+	testbeds := []struct {
+		name      string
+		newBandit func(rng *rand.Rand) bandit.Bandit
+		isBinary  bool
+	}{
+		{"10-armed gaussian", func(rng *rand.Rand) bandit.Bandit { return bandit.NewGaussian(10, rng) }, false},
+		{"10-armed bernoulli", func(rng *rand.Rand) bandit.Bandit { return bandit.NewBernoulli(10, rng) }, true},
+		{"10-armed drifting", func(rng *rand.Rand) bandit.Bandit { return bandit.NewDrifting(10, 0.01, rng) }, false},
+	}
+	for _, testbed := range testbeds {
+		fmt.Println("=========================================")
+		fmt.Printf("%s, %d runs, last %d of %d steps:\n", testbed.name, runs, steps/10, steps)
+		testbedAgents := agents
+		if testbed.isBinary {
+			testbedAgents = append(testbedAgents, betaThompson)
+		}
+		for _, agent := range testbedAgents {
+			printResult(agent.name, bandit.Run(runs, steps, 0, testbed.newBandit, agent.newAgent))
+		}
+	}
+	fmt.Println("=========================================")
+}
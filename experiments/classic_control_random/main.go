@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+
+	"reinforcement-learning-playground/classiccontrol"
+)
+
+func iterEpisodes(episodes int, env classiccontrol.Env, rng *rand.Rand) (float64, float64) {
+	actions := env.Actions()
+	totalReward := 0.0
+	totalLength := 0
+	for i := 0; i < episodes; i++ {
+		env.Reset()
+		for {
+			action := actions[rng.Intn(len(actions))]
+			_, reward, terminated, truncated := env.Step(action)
+			totalReward += reward
+			totalLength++
+			if terminated || truncated {
+				break
+			}
+		}
+	}
+	return totalReward / float64(episodes), float64(totalLength) / float64(episodes)
+}
+
+func main() {
+	rng := rand.New(rand.NewSource(0))
+	envs := []struct {
+		name string
+		env  classiccontrol.Env
+	}{
+		{"MountainCar", classiccontrol.NewMountainCar(rng)},
+		{"CartPole", classiccontrol.NewCartPole(rng)},
+		{"Acrobot", classiccontrol.NewAcrobot(rng)},
+	}
+	fmt.Println("=========================================")
+	fmt.Println("Uniform random policy, 100 episodes:")
+	for _, e := range envs {
+		meanReward, meanLength := iterEpisodes(100, e.env, rng)
+		fmt.Printf("%-12s return %8.2f  length %6.1f\n", e.name, meanReward, meanLength)
+	}
+	fmt.Println("=========================================")
+}
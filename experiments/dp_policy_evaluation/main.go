@@ -6,6 +6,7 @@ import (
 
 	collections "github.com/marubontan/go-collections"
 	"github.com/marubontan/go-maze/maze"
+	"reinforcement-learning-playground/gridworld"
 )
 
 func composeDungen() *maze.Maze {
@@ -33,13 +34,6 @@ func printDungenConf() {
 	fmt.Println(("G: Goal with Reward 1"))
 }
 
-const (
-	Left = iota
-	Right
-	Up
-	Down
-)
-
 func newV() *collections.DefaultDict[[2]int, float64] {
 	v := collections.NewDefaultDict[[2]int, float64]()
 	return &v
@@ -61,35 +55,11 @@ func getStates(m *maze.Maze) State {
 
 type Policy map[[2]int]map[int]float64
 
-var actions = []int{Left, Right, Up, Down}
-
-func nextBlockAvailable(x, y, action int, m *maze.Maze) bool {
-	nextX := x
-	nextY := y
-	switch action {
-	case Left:
-		nextX--
-	case Right:
-		nextX++
-	case Up:
-		nextY--
-	case Down:
-		nextY++
-	}
-	return m.IsAvailable(nextX, nextY)
-
-}
-
-func newPolicy(states [][2]int, m *maze.Maze) Policy {
+func newPolicy(states [][2]int, w *gridworld.World) Policy {
 	var policy Policy = make(map[[2]int]map[int]float64)
 	for _, state := range states {
 		statePolicy := make(map[int]float64)
-		availableActions := make([]int, 0)
-		for _, action := range actions {
-			if nextBlockAvailable(state[0], state[1], action, m) {
-				availableActions = append(availableActions, action)
-			}
-		}
+		availableActions := w.LegalActions(state)
 		for _, action := range availableActions {
 			statePolicy[action] = 1.0 / float64(len(availableActions))
 		}
@@ -109,9 +79,9 @@ func getReward(state [2]int, goalX, goalY int) float64 {
 	return 0
 }
 
-func evalStep(policy Policy, v *collections.DefaultDict[[2]int, float64], dungeon *maze.Maze, gamma float64) *collections.DefaultDict[[2]int, float64] {
-	states := getStates(dungeon)
-	goalX, goalY, err := dungeon.GetGoal()
+func evalStep(policy Policy, v *collections.DefaultDict[[2]int, float64], world *gridworld.World, gamma float64) *collections.DefaultDict[[2]int, float64] {
+	states := getStates(world.Maze)
+	goalX, goalY, err := world.Maze.GetGoal()
 	if err != nil {
 		panic(err)
 	}
@@ -124,19 +94,7 @@ func evalStep(policy Policy, v *collections.DefaultDict[[2]int, float64], dungeo
 		statePolicy := policy[state]
 		var newV float64
 		for action, prob := range statePolicy {
-			var nextState [2]int
-			nextState[0] = state[0]
-			nextState[1] = state[1]
-			switch action {
-			case Left:
-				nextState[0]--
-			case Right:
-				nextState[0]++
-			case Up:
-				nextState[1]--
-			case Down:
-				nextState[1]++
-			}
+			nextState := world.NextState(state, action)
 			reward := getReward(nextState, goalX, goalY)
 			newV += prob * (reward + gamma*v.Get(nextState))
 		}
@@ -146,13 +104,13 @@ func evalStep(policy Policy, v *collections.DefaultDict[[2]int, float64], dungeo
 	return v
 
 }
-func evalPolicy(policy Policy, v *collections.DefaultDict[[2]int, float64], dungeon *maze.Maze, gamma float64) {
+func evalPolicy(policy Policy, v *collections.DefaultDict[[2]int, float64], world *gridworld.World, gamma float64) {
 	for {
 		oldV := collections.NewDefaultDict[[2]int, float64]()
 		for state, value := range v.Data {
 			oldV.Set(state, value)
 		}
-		evalStep(policy, v, dungeon, gamma)
+		evalStep(policy, v, world, gamma)
 		var delta float64 = -1
 		for state := range v.Data {
 			if presentDelta := math.Abs(oldV.Get(state) - v.Get(state)); presentDelta > delta {
@@ -173,8 +131,9 @@ func main() {
 	fmt.Println("=========================================")
 	printDungenConf()
 	fmt.Println("=========================================")
+	world := gridworld.New(dungeon, gridworld.FourWay)
 	v := newV()
-	policy := newPolicy(getStates(dungeon), dungeon)
-	evalPolicy(policy, v, dungeon, 0.9)
+	policy := newPolicy(getStates(dungeon), world)
+	evalPolicy(policy, v, world, 0.9)
 	fmt.Println(v)
 }
@@ -6,6 +6,7 @@ import (
 
 	collections "github.com/marubontan/go-collections"
 	"github.com/marubontan/go-maze/maze"
+	"reinforcement-learning-playground/gridworld"
 )
 
 func composeDungen() *maze.Maze {
@@ -33,13 +34,6 @@ func printDungenConf() {
 	fmt.Println(("G: Goal with Reward 1"))
 }
 
-const (
-	Left = iota
-	Right
-	Up
-	Down
-)
-
 func newV() *collections.DefaultDict[[2]int, float64] {
 	v := collections.NewDefaultDict[[2]int, float64]()
 	return &v
@@ -59,14 +53,12 @@ func getStates(m *maze.Maze) State {
 
 type Policy map[[2]int]map[int]float64
 
-var actions = []int{Left, Right, Up, Down}
-
-func newPolicy(states [][2]int, m *maze.Maze) Policy {
+func newPolicy(states [][2]int, w *gridworld.World) Policy {
 	var policy Policy = make(map[[2]int]map[int]float64)
 	for _, state := range states {
 		statePolicy := make(map[int]float64)
-		for _, action := range actions {
-			statePolicy[action] = 0.25
+		for _, action := range w.Actions() {
+			statePolicy[action] = 1.0 / float64(len(w.Actions()))
 		}
 		policy[state] = statePolicy
 	}
@@ -74,15 +66,6 @@ func newPolicy(states [][2]int, m *maze.Maze) Policy {
 
 }
 
-func getNextState(prevState [2]int, newStateCandidate [2]int, m *maze.Maze) [2]int {
-	if m.IsAvailable(newStateCandidate[0], newStateCandidate[1]) {
-		return newStateCandidate
-
-	}
-	return prevState
-
-}
-
 func getReward(state [2]int, goalX, goalY int) float64 {
 	if state[0] == goalX && state[1] == goalY {
 		return 1.0
@@ -93,9 +76,9 @@ func getReward(state [2]int, goalX, goalY int) float64 {
 	return 0
 }
 
-func evalStep(policy Policy, v *collections.DefaultDict[[2]int, float64], dungeon *maze.Maze, gamma float64) *collections.DefaultDict[[2]int, float64] {
-	states := getStates(dungeon)
-	goalX, goalY, err := dungeon.GetGoal()
+func evalStep(policy Policy, v *collections.DefaultDict[[2]int, float64], world *gridworld.World, gamma float64) *collections.DefaultDict[[2]int, float64] {
+	states := getStates(world.Maze)
+	goalX, goalY, err := world.Maze.GetGoal()
 	if err != nil {
 		panic(err)
 	}
@@ -108,20 +91,7 @@ func evalStep(policy Policy, v *collections.DefaultDict[[2]int, float64], dungeo
 		statePolicy := policy[state]
 		var newV float64
 		for action, prob := range statePolicy {
-			var nextStateCandidate [2]int
-			nextStateCandidate[0] = state[0]
-			nextStateCandidate[1] = state[1]
-			switch action {
-			case Left:
-				nextStateCandidate[0]--
-			case Right:
-				nextStateCandidate[0]++
-			case Up:
-				nextStateCandidate[1]--
-			case Down:
-				nextStateCandidate[1]++
-			}
-			nextState := getNextState(state, nextStateCandidate, dungeon)
+			nextState := world.NextState(state, action)
 			reward := getReward(nextState, goalX, goalY)
 			newV += prob * (reward + gamma*v.Get(nextState))
 		}
@@ -132,13 +102,13 @@ func evalStep(policy Policy, v *collections.DefaultDict[[2]int, float64], dungeo
 
 }
 
-func evalPolicy(policy Policy, v *collections.DefaultDict[[2]int, float64], dungeon *maze.Maze, gamma float64) {
+func evalPolicy(policy Policy, v *collections.DefaultDict[[2]int, float64], world *gridworld.World, gamma float64) {
 	for {
 		oldV := collections.NewDefaultDict[[2]int, float64]()
 		for state, value := range v.Data {
 			oldV.Set(state, value)
 		}
-		evalStep(policy, v, dungeon, gamma)
+		evalStep(policy, v, world, gamma)
 		var delta float64 = -1
 		for state := range v.Data {
 			if presentDelta := math.Abs(oldV.Get(state) - v.Get(state)); presentDelta > delta {
@@ -159,8 +129,9 @@ func main() {
 	fmt.Println("=========================================")
 	printDungenConf()
 	fmt.Println("=========================================")
+	world := gridworld.New(dungeon, gridworld.FourWay)
 	v := newV()
-	policy := newPolicy(getStates(dungeon), dungeon)
-	evalPolicy(policy, v, dungeon, 0.9)
+	policy := newPolicy(getStates(dungeon), world)
+	evalPolicy(policy, v, world, 0.9)
 	fmt.Println(v)
 }
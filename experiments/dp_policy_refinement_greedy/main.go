@@ -6,6 +6,8 @@ import (
 
 	collections "github.com/marubontan/go-collections"
 	"github.com/marubontan/go-maze/maze"
+	"reinforcement-learning-playground/explorer"
+	"reinforcement-learning-playground/gridworld"
 )
 
 func composeDungen() *maze.Maze {
@@ -33,13 +35,6 @@ func printDungenConf() {
 	fmt.Println(("G: Goal with Reward 1"))
 }
 
-const (
-	Left = iota
-	Right
-	Up
-	Down
-)
-
 func newV() *collections.DefaultDict[[2]int, float64] {
 	v := collections.NewDefaultDict[[2]int, float64]()
 	return &v
@@ -59,14 +54,12 @@ func getStates(m *maze.Maze) State {
 
 type Policy map[[2]int]map[int]float64
 
-var actions = []int{Left, Right, Up, Down}
-
-func newPolicy(states [][2]int, m *maze.Maze) Policy {
+func newPolicy(states [][2]int, w *gridworld.World) Policy {
 	var policy Policy = make(map[[2]int]map[int]float64)
 	for _, state := range states {
 		statePolicy := make(map[int]float64)
-		for _, action := range actions {
-			statePolicy[action] = 0.25
+		for _, action := range w.Actions() {
+			statePolicy[action] = 1.0 / float64(len(w.Actions()))
 		}
 		policy[state] = statePolicy
 	}
@@ -74,15 +67,6 @@ func newPolicy(states [][2]int, m *maze.Maze) Policy {
 
 }
 
-func getNextState(prevState [2]int, newStateCandidate [2]int, m *maze.Maze) [2]int {
-	if m.IsAvailable(newStateCandidate[0], newStateCandidate[1]) {
-		return newStateCandidate
-
-	}
-	return prevState
-
-}
-
 func getReward(state [2]int, goalX, goalY int) float64 {
 	if state[0] == goalX && state[1] == goalY {
 		return 1.0
@@ -93,9 +77,9 @@ func getReward(state [2]int, goalX, goalY int) float64 {
 	return 0
 }
 
-func evalStep(policy Policy, v *collections.DefaultDict[[2]int, float64], dungeon *maze.Maze, gamma float64) *collections.DefaultDict[[2]int, float64] {
-	states := getStates(dungeon)
-	goalX, goalY, err := dungeon.GetGoal()
+func evalStep(policy Policy, v *collections.DefaultDict[[2]int, float64], world *gridworld.World, gamma float64) *collections.DefaultDict[[2]int, float64] {
+	states := getStates(world.Maze)
+	goalX, goalY, err := world.Maze.GetGoal()
 	if err != nil {
 		panic(err)
 	}
@@ -108,20 +92,7 @@ func evalStep(policy Policy, v *collections.DefaultDict[[2]int, float64], dungeo
 		statePolicy := policy[state]
 		var newV float64
 		for action, prob := range statePolicy {
-			var nextStateCandidate [2]int
-			nextStateCandidate[0] = state[0]
-			nextStateCandidate[1] = state[1]
-			switch action {
-			case Left:
-				nextStateCandidate[0]--
-			case Right:
-				nextStateCandidate[0]++
-			case Up:
-				nextStateCandidate[1]--
-			case Down:
-				nextStateCandidate[1]++
-			}
-			nextState := getNextState(state, nextStateCandidate, dungeon)
+			nextState := world.NextState(state, action)
 			reward := getReward(nextState, goalX, goalY)
 			newV += prob * (reward + gamma*v.Get(nextState))
 		}
@@ -131,28 +102,9 @@ func evalStep(policy Policy, v *collections.DefaultDict[[2]int, float64], dungeo
 	return v
 
 }
-func argmax(data map[int]float64) int {
-	var maxKey int
-	var maxValue float64
-
-	for key, value := range data {
-		maxKey = key
-		maxValue = value
-		break
-	}
 
-	for key, value := range data {
-		if value > maxValue {
-			maxKey = key
-			maxValue = value
-		}
-	}
-
-	return maxKey
-}
-
-func updatePolicy(policy *Policy, v *collections.DefaultDict[[2]int, float64], dungeon *maze.Maze, gamma float64) *Policy {
-	goalX, goalY, err := dungeon.GetGoal()
+func updatePolicy(policy *Policy, v *collections.DefaultDict[[2]int, float64], world *gridworld.World, gamma float64) *Policy {
+	goalX, goalY, err := world.Maze.GetGoal()
 	if err != nil {
 		panic(err)
 	}
@@ -160,46 +112,32 @@ func updatePolicy(policy *Policy, v *collections.DefaultDict[[2]int, float64], d
 	for state, statePolicy := range *policy {
 		actionValues := make(map[int]float64)
 		for action := range statePolicy {
-			var nextStateCandidate [2]int
-			nextStateCandidate[0] = state[0]
-			nextStateCandidate[1] = state[1]
-			switch action {
-			case Left:
-				nextStateCandidate[0]--
-			case Right:
-				nextStateCandidate[0]++
-			case Up:
-				nextStateCandidate[1]--
-			case Down:
-				nextStateCandidate[1]++
-			}
-			nextState := getNextState(state, nextStateCandidate, dungeon)
+			nextState := world.NextState(state, action)
 			reward := getReward(nextState, goalX, goalY)
 			actionValues[action] = reward + gamma*v.Get(nextState)
 		}
-		maxAction := argmax(actionValues)
+		maxActions := explorer.ArgmaxAll(actionValues)
 
 		updatedStatePolicy := make(map[int]float64)
-		for _, action := range actions {
-			if action == maxAction {
-				updatedStatePolicy[action] = 1.0
-			} else {
-				updatedStatePolicy[action] = 0.0
-			}
+		for _, action := range world.Actions() {
+			updatedStatePolicy[action] = 0.0
+		}
+		for _, action := range maxActions {
+			updatedStatePolicy[action] = 1.0 / float64(len(maxActions))
 		}
 		updatedPolicy[state] = updatedStatePolicy
 	}
 	return &updatedPolicy
 }
 
-func iterPolicy(policy Policy, v *collections.DefaultDict[[2]int, float64], dungeon *maze.Maze, gamma float64) Policy {
+func iterPolicy(policy Policy, v *collections.DefaultDict[[2]int, float64], world *gridworld.World, gamma float64) Policy {
 	for {
 		oldV := collections.NewDefaultDict[[2]int, float64]()
 		for state, value := range v.Data {
 			oldV.Set(state, value)
 		}
-		evalStep(policy, v, dungeon, gamma)
-		updatedPolicy := updatePolicy(&policy, v, dungeon, gamma)
+		evalStep(policy, v, world, gamma)
+		updatedPolicy := updatePolicy(&policy, v, world, gamma)
 		var delta float64 = -1
 		for state := range v.Data {
 			if presentDelta := math.Abs(oldV.Get(state) - v.Get(state)); presentDelta > delta {
@@ -221,9 +159,10 @@ func main() {
 	fmt.Println("=========================================")
 	printDungenConf()
 	fmt.Println("=========================================")
+	world := gridworld.New(dungeon, gridworld.FourWay)
 	v := newV()
-	policy := newPolicy(getStates(dungeon), dungeon)
-	updatedPolicy := iterPolicy(policy, v, dungeon, 0.9)
+	policy := newPolicy(getStates(dungeon), world)
+	updatedPolicy := iterPolicy(policy, v, world, 0.9)
 	fmt.Println(v)
 	fmt.Println(updatedPolicy)
 }
@@ -0,0 +1,279 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/marubontan/go-maze/maze"
+	"reinforcement-learning-playground/classiccontrol"
+	"reinforcement-learning-playground/explorer"
+	"reinforcement-learning-playground/gridworld"
+	"reinforcement-learning-playground/nn"
+	"reinforcement-learning-playground/replay"
+	"reinforcement-learning-playground/schedule"
+)
+
+func composeDungen() *maze.Maze {
+	dungeon := maze.NewMaze(3, 4)
+	var err error
+	err = dungeon.SetStart(0, 2)
+	if err != nil {
+		panic(err)
+	}
+	err = dungeon.SetGoal(3, 0)
+	if err != nil {
+		panic(err)
+	}
+	err = dungeon.SetObstacle(1, 1)
+	if err != nil {
+		panic(err)
+	}
+	return dungeon
+}
+
+func printDungenConf() {
+	fmt.Println("Dungeon Configuration:")
+	fmt.Println("S: Start Position")
+	fmt.Println("X: Obstacle")
+	fmt.Println(("G: Goal with Reward 1"))
+}
+
+func getReward(state [2]int, goalX, goalY int) float64 {
+	if state[0] == goalX && state[1] == goalY {
+		return 1.0
+	}
+	if state[0] == 3 && state[1] == 1 {
+		return -1.0
+	}
+	return 0
+}
+
+type Agent struct {
+	gamma     float64
+	explorer  explorer.Explorer[explorer.NoState]
+	actions   []int
+	q         nn.Sequential
+	target    nn.Sequential
+	optimizer nn.Optimizer
+	buffer    replay.Buffer
+	batchSize int
+	syncEvery int
+	steps     int
+	rng       *rand.Rand
+}
+
+func newAgent(gamma float64, lr float64, explorer explorer.Explorer[explorer.NoState], sizes []int, buffer replay.Buffer, batchSize int, syncEvery int, actions []int, rng *rand.Rand) *Agent {
+	q := nn.NewMLP(sizes, nn.ReLU, rng)
+	target := nn.NewMLP(sizes, nn.ReLU, rng)
+	nn.CopyParams(target, q)
+	return &Agent{
+		gamma:     gamma,
+		explorer:  explorer,
+		actions:   actions,
+		q:         q,
+		target:    target,
+		optimizer: nn.NewAdam(q.Params(), lr),
+		buffer:    buffer,
+		batchSize: batchSize,
+		syncEvery: syncEvery,
+		rng:       rng,
+	}
+}
+
+func (a *Agent) stateValues(observation []float64) map[int]float64 {
+	values := a.q.Forward(nn.New(1, len(observation), observation))
+	stateQ := make(map[int]float64)
+	for _, action := range a.actions {
+		stateQ[action] = values.Data[action]
+	}
+	return stateQ
+}
+
+func (a *Agent) getAction(observation []float64) (int, error) {
+	statePolicy := a.explorer.Probs(explorer.NoState{}, a.stateValues(observation))
+	cumProb := 0.0
+	sample := a.rng.Float64()
+	for _, action := range a.actions {
+		cumProb += statePolicy[action]
+		if sample < cumProb {
+			return action, nil
+		}
+	}
+	return -1, errors.New("action not found")
+}
+
+func (a *Agent) update(observation []float64, action int, reward float64, nextObservation []float64, isGoal bool) {
+	a.buffer.Add(replay.Transition{
+		State:     observation,
+		Action:    action,
+		Reward:    reward,
+		NextState: nextObservation,
+		IsGoal:    isGoal,
+	})
+	a.steps++
+	a.explorer.Step()
+	if a.buffer.Len() >= a.batchSize {
+		a.learn()
+	}
+	if a.steps%a.syncEvery == 0 {
+		nn.CopyParams(a.target, a.q)
+	}
+}
+
+func (a *Agent) learn() {
+	batch := a.buffer.Sample(a.batchSize)
+	size := len(batch.Transitions)
+	states := make([][]float64, size)
+	nextStates := make([][]float64, size)
+	actions := make([]int, size)
+	for i, transition := range batch.Transitions {
+		states[i] = transition.State
+		nextStates[i] = transition.NextState
+		actions[i] = transition.Action
+	}
+	nextQ := a.target.Forward(nn.FromRows(nextStates))
+	targets := nn.Zeros(size, 1)
+	for i, transition := range batch.Transitions {
+		maxQ := 0.0
+		if !transition.IsGoal {
+			next := nextQ.Row(i)
+			maxQ = next[0]
+			for _, value := range next[1:] {
+				if value > maxQ {
+					maxQ = value
+				}
+			}
+		}
+		targets.Data[i] = transition.Reward + a.gamma*maxQ
+	}
+	prediction := nn.Gather(a.q.Forward(nn.FromRows(states)), actions)
+	tdErrors := make([]float64, size)
+	for i := range tdErrors {
+		tdErrors[i] = targets.Data[i] - prediction.Data[i]
+	}
+	weights := nn.New(size, 1, batch.Weights)
+	loss := nn.Mean(nn.Mul(nn.HuberElements(prediction, targets, 1.0), weights))
+	a.optimizer.ZeroGrad()
+	loss.Backward()
+	nn.ClipGradNorm(a.q.Params(), 10.0)
+	a.optimizer.Step()
+	a.buffer.UpdatePriorities(batch.Indices, tdErrors)
+}
+
+func iterSteps(steps int, agent *Agent, env classiccontrol.Env) []float64 {
+	returns := make([]float64, 0)
+	observation := env.Reset()
+	g := 0.0
+	for i := 0; i < steps; i++ {
+		action, err := agent.getAction(observation)
+		if err != nil {
+			panic(err)
+		}
+		nextObservation, reward, isGoal, truncated := env.Step(action)
+		agent.update(observation, action, reward, nextObservation, isGoal)
+		g += reward
+		if isGoal || truncated {
+			returns = append(returns, g)
+			observation = env.Reset()
+			g = 0.0
+		} else {
+			observation = nextObservation
+		}
+	}
+	return returns
+}
+
+func evaluate(episodes int, agent *Agent, env classiccontrol.Env) float64 {
+	sum := 0.0
+	for i := 0; i < episodes; i++ {
+		observation := env.Reset()
+		for {
+			action := explorer.Argmax(agent.stateValues(observation), agent.rng)
+			nextObservation, reward, isGoal, truncated := env.Step(action)
+			sum += reward
+			if isGoal || truncated {
+				break
+			}
+			observation = nextObservation
+		}
+	}
+	return sum / float64(episodes)
+}
+
+func printReturns(returns []float64, window int) {
+	for i := 0; i+window <= len(returns); i += window {
+		sum := 0.0
+		for _, g := range returns[i : i+window] {
+			sum += g
+		}
+		fmt.Printf("episodes %4d-%4d: %.1f\n", i+1, i+window, sum/float64(window))
+	}
+}
+
+func main() {
+	dungeon := composeDungen()
+	fmt.Println("=========================================")
+	fmt.Println("Dungeon:")
+	dungeon.Print()
+	fmt.Println("=========================================")
+	printDungenConf()
+	fmt.Println("=========================================")
+	world := gridworld.New(dungeon, gridworld.FourWay)
+	rng := rand.New(rand.NewSource(0))
+	gridEnv := gridworld.NewEnv(world, getReward, [2]int{0, 0}, 100)
+	gridExplorer := explorer.NewEpsilonGreedy[explorer.NoState](schedule.NewLinear(1.0, 0.1, 2000))
+	gridAgent := newAgent(0.9, 1e-3, gridExplorer, []int{gridEnv.Dim(), 32, world.ActionSet.Count()}, replay.NewUniform(5000, rng), 32, 100, world.Actions(), rng)
+	iterSteps(5000, gridAgent, gridEnv)
+	q := make(map[[2]int]map[int]float64)
+	for hI, hBlocks := range dungeon.Blocks {
+		for wI := range hBlocks {
+			state := [2]int{wI, hI}
+			q[state] = gridAgent.stateValues(gridEnv.Observation(state))
+		}
+	}
+	fmt.Println(q)
+	fmt.Println("=========================================")
+
+	start := time.Now()
+	cartPole := classiccontrol.NewCartPole(rng)
+	cartExplorer := explorer.NewEpsilonGreedy[explorer.NoState](schedule.NewLinear(1.0, 0.05, 10000))
+	cartAgent := newAgent(0.99, 5e-4, cartExplorer, []int{4, 64, 64, len(cartPole.Actions())}, replay.NewUniform(50000, rng), 64, 500, cartPole.Actions(), rng)
+	returns := iterSteps(100000, cartAgent, cartPole)
+	fmt.Println("CartPole mean return per 50 episodes:")
+	printReturns(returns, 50)
+	fmt.Printf("trained for 100000 steps in %s\n", time.Since(start).Round(time.Second))
+	fmt.Printf("greedy mean return over 10 episodes: %.1f\n", evaluate(10, cartAgent, cartPole))
+	fmt.Println("=========================================")
+
+	// The same agent with each replay buffer, for a shorter run.
+	steps := 30000
+	buffers := []struct {
+		name      string
+		newBuffer func(rng *rand.Rand) replay.Buffer
+	}{
+		{"uniform", func(rng *rand.Rand) replay.Buffer {
+			return replay.NewUniform(50000, rng)
+		}},
+		{"proportional", func(rng *rand.Rand) replay.Buffer {
+			return replay.NewProportional(50000, 0.6, schedule.NewLinear(0.4, 1.0, steps), rng)
+		}},
+		{"rank", func(rng *rand.Rand) replay.Buffer {
+			return replay.NewRank(50000, 0.7, schedule.NewLinear(0.5, 1.0, steps), 1000, rng)
+		}},
+	}
+	for _, b := range buffers {
+		start := time.Now()
+		rng := rand.New(rand.NewSource(0))
+		cartPole := classiccontrol.NewCartPole(rng)
+		strategy := explorer.NewEpsilonGreedy[explorer.NoState](schedule.NewLinear(1.0, 0.05, 10000))
+		agent := newAgent(0.99, 5e-4, strategy, []int{4, 64, 64, len(cartPole.Actions())}, b.newBuffer(rng), 64, 500, cartPole.Actions(), rng)
+		returns := iterSteps(steps, agent, cartPole)
+		fmt.Printf("%s replay, CartPole mean return per 50 episodes:\n", b.name)
+		printReturns(returns, 50)
+		fmt.Printf("trained for %d steps in %s\n", steps, time.Since(start).Round(time.Second))
+		fmt.Printf("greedy mean return over 10 episodes: %.1f\n", evaluate(10, agent, cartPole))
+		fmt.Println("=========================================")
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+
+	"reinforcement-learning-playground/blackjack"
+)
+
+type Policy func(state blackjack.State) int
+
+func stickOn20(state blackjack.State) int {
+	if state[0] >= 20 {
+		return blackjack.Stick
+	}
+	return blackjack.Hit
+}
+
+type Agent struct {
+	gamma  float64
+	policy Policy
+	memory []Memory
+	cnt    map[blackjack.State]int
+	v      map[blackjack.State]float64
+}
+
+type Memory struct {
+	state  blackjack.State
+	action int
+	reward float64
+}
+
+func newAgent(gamma float64, policy Policy) *Agent {
+	return &Agent{
+		gamma:  gamma,
+		policy: policy,
+		memory: make([]Memory, 0),
+		cnt:    make(map[blackjack.State]int),
+		v:      make(map[blackjack.State]float64),
+	}
+}
+
+func (a *Agent) addMemory(state blackjack.State, action int, reward float64) {
+	a.memory = append(a.memory, Memory{state, action, reward})
+}
+
+func (a *Agent) getAction(state blackjack.State) int {
+	return a.policy(state)
+}
+
+func (a *Agent) eval() {
+	g := 0.0
+	for i := len(a.memory) - 1; i >= 0; i-- {
+		memory := a.memory[i]
+		g = a.gamma*g + memory.reward
+		a.cnt[memory.state]++
+		a.v[memory.state] += (g - a.v[memory.state]) / float64(a.cnt[memory.state])
+	}
+}
+
+func (a *Agent) reset() {
+	a.memory = make([]Memory, 0)
+}
+
+func iterEpisodes(episodes int, agent *Agent, env *blackjack.Blackjack) {
+	for i := 0; i < episodes; i++ {
+		state := env.Reset()
+		agent.reset()
+		for {
+			action := agent.getAction(state)
+			nextState, reward, done := env.Step(action)
+			agent.addMemory(state, action, reward)
+			if done {
+				agent.eval()
+				break
+			}
+			state = nextState
+		}
+	}
+}
+
+func printValues(v map[blackjack.State]float64, usableAce int) {
+	fmt.Print("sum\\dealer")
+	for dealer := 1; dealer <= 10; dealer++ {
+		fmt.Printf("%6d", dealer)
+	}
+	fmt.Print("\n")
+	for sum := 21; sum >= 12; sum-- {
+		fmt.Printf("%10d", sum)
+		for dealer := 1; dealer <= 10; dealer++ {
+			fmt.Printf("%6.2f", v[blackjack.State{sum, dealer, usableAce}])
+		}
+		fmt.Print("\n")
+	}
+}
+
+func main() {
+	fmt.Println("=========================================")
+	fmt.Println("Blackjack: player sticks on 20 or 21")
+	fmt.Println("=========================================")
+	rng := rand.New(rand.NewSource(0))
+	env := blackjack.New(rng)
+	agent := newAgent(1.0, stickOn20)
+	iterEpisodes(500000, agent, env)
+	fmt.Println("Usable ace:")
+	printValues(agent.v, 1)
+	fmt.Println("No usable ace:")
+	printValues(agent.v, 0)
+}
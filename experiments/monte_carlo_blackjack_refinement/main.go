@@ -0,0 +1,152 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+
+	"reinforcement-learning-playground/blackjack"
+	"reinforcement-learning-playground/explorer"
+	"reinforcement-learning-playground/learningrate"
+	"reinforcement-learning-playground/schedule"
+)
+
+type Agent struct {
+	gamma    float64
+	explorer explorer.Explorer[blackjack.State]
+	memory   []Memory
+	alpha    learningrate.Rate
+	visits   map[blackjack.State]map[int]int
+	steps    int
+	q        map[blackjack.State]map[int]float64
+	actions  []int
+	rng      *rand.Rand
+}
+
+type Memory struct {
+	state  blackjack.State
+	action int
+	reward float64
+}
+
+func newAgent(gamma float64, explorer explorer.Explorer[blackjack.State], alpha learningrate.Rate, actions []int, rng *rand.Rand) *Agent {
+	return &Agent{
+		gamma:    gamma,
+		explorer: explorer,
+		memory:   make([]Memory, 0),
+		alpha:    alpha,
+		visits:   make(map[blackjack.State]map[int]int),
+		q:        make(map[blackjack.State]map[int]float64),
+		actions:  actions,
+		rng:      rng,
+	}
+}
+
+// ensureState adds the action values of a state the first time it is dealt,
+// since the reachable states are not enumerated up front.
+func (a *Agent) ensureState(state blackjack.State) {
+	if _, ok := a.q[state]; ok {
+		return
+	}
+	a.q[state] = make(map[int]float64)
+	a.visits[state] = make(map[int]int)
+	for _, action := range a.actions {
+		a.q[state][action] = a.explorer.InitialQ()
+		a.visits[state][action] = 0
+	}
+}
+
+func (a *Agent) addMemory(state blackjack.State, action int, reward float64) {
+	a.memory = append(a.memory, Memory{state, action, reward})
+}
+
+func (a *Agent) getAction(state blackjack.State) (int, error) {
+	a.ensureState(state)
+	statePolicy := a.explorer.Probs(state, a.q[state])
+	cumProb := 0.0
+	sample := a.rng.Float64()
+	for _, action := range a.actions {
+		cumProb += statePolicy[action]
+		if sample < cumProb {
+			a.explorer.Observe(state, action)
+			return action, nil
+		}
+	}
+	return -1, errors.New("action not found")
+}
+
+func (a *Agent) stepSize(state blackjack.State, action int) float64 {
+	a.visits[state][action]++
+	a.steps++
+	return a.alpha.Alpha(a.visits[state][action], a.steps)
+}
+
+func (a *Agent) updatePolicy() {
+	g := 0.0
+	for i := len(a.memory) - 1; i >= 0; i-- {
+		memory := a.memory[i]
+		g = a.gamma*g + memory.reward
+		a.q[memory.state][memory.action] += (g - a.q[memory.state][memory.action]) * a.stepSize(memory.state, memory.action)
+	}
+}
+
+func (a *Agent) reset() {
+	a.memory = make([]Memory, 0)
+}
+
+func iterEpisodes(episodes int, agent *Agent, env *blackjack.Blackjack) {
+	for i := 0; i < episodes; i++ {
+		state := env.Reset()
+		agent.reset()
+		for {
+			action, err := agent.getAction(state)
+			if err != nil {
+				panic(err)
+			}
+			nextState, reward, done := env.Step(action)
+			agent.addMemory(state, action, reward)
+			if done {
+				agent.updatePolicy()
+				agent.explorer.Step()
+				break
+			}
+			state = nextState
+		}
+	}
+}
+
+func printPolicy(q map[blackjack.State]map[int]float64, usableAce int) {
+	fmt.Print("sum\\dealer")
+	for dealer := 1; dealer <= 10; dealer++ {
+		fmt.Printf("%3d", dealer)
+	}
+	fmt.Print("\n")
+	for sum := 21; sum >= 12; sum-- {
+		fmt.Printf("%10d", sum)
+		for dealer := 1; dealer <= 10; dealer++ {
+			stateQ := q[blackjack.State{sum, dealer, usableAce}]
+			if stateQ[blackjack.Hit] > stateQ[blackjack.Stick] {
+				fmt.Print("  H")
+			} else {
+				fmt.Print("  S")
+			}
+		}
+		fmt.Print("\n")
+	}
+}
+
+func main() {
+	fmt.Println("=========================================")
+	fmt.Println("Blackjack: on-policy epsilon-greedy MC control")
+	fmt.Println("H: Hit, S: Stick")
+	fmt.Println("=========================================")
+	rng := rand.New(rand.NewSource(0))
+	env := blackjack.New(rng)
+	strategy := explorer.NewEpsilonGreedy[blackjack.State](schedule.Constant(0.1))
+	agent := newAgent(1.0, strategy, learningrate.Harmonic{}, blackjack.Actions(), rng)
+	iterEpisodes(1000000, agent, env)
+	fmt.Println("Usable ace:")
+	printPolicy(agent.q, 1)
+	fmt.Println("No usable ace:")
+	printPolicy(agent.q, 0)
+}
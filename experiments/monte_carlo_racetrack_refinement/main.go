@@ -0,0 +1,289 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+
+	"reinforcement-learning-playground/explorer"
+	"reinforcement-learning-playground/learningrate"
+)
+
+const (
+	Wall = iota
+	Track
+	StartLine
+	FinishLine
+)
+
+var trackMap = []string{
+	"###.....FF",
+	"##......FF",
+	"##......FF",
+	"#.......##",
+	"#......###",
+	"#......###",
+	".......###",
+	"......####",
+	"......####",
+	".....#####",
+	".....#####",
+	"SSSSS#####",
+}
+
+const maxSpeed = 4
+
+// State is the car position (column, row) and velocity (right, up).
+type State [4]int
+
+type Racetrack struct {
+	blocks [][]int
+	starts [][2]int
+	noise  float64
+	pos    [2]int
+	vel    [2]int
+	rng    *rand.Rand
+}
+
+func composeTrack(rows []string, noise float64, rng *rand.Rand) *Racetrack {
+	blocks := make([][]int, len(rows))
+	starts := make([][2]int, 0)
+	for y, row := range rows {
+		blocks[y] = make([]int, len(row))
+		for x, c := range row {
+			switch c {
+			case '.':
+				blocks[y][x] = Track
+			case 'S':
+				blocks[y][x] = StartLine
+				starts = append(starts, [2]int{x, y})
+			case 'F':
+				blocks[y][x] = FinishLine
+			default:
+				blocks[y][x] = Wall
+			}
+		}
+	}
+	return &Racetrack{blocks: blocks, starts: starts, noise: noise, rng: rng}
+}
+
+func printTrackConf() {
+	fmt.Println("Racetrack Configuration:")
+	fmt.Println("S: Start Line")
+	fmt.Println("F: Finish Line")
+	fmt.Println("#: Off Track, back to S with zero velocity")
+	fmt.Println("-1 per step, velocity components in [0, 4]")
+}
+
+func (r *Racetrack) blockAt(x, y int) int {
+	if y < 0 || y >= len(r.blocks) || x < 0 || x >= len(r.blocks[y]) {
+		return Wall
+	}
+	return r.blocks[y][x]
+}
+
+func (r *Racetrack) state() State {
+	return State{r.pos[0], r.pos[1], r.vel[0], r.vel[1]}
+}
+
+func (r *Racetrack) reset() State {
+	r.pos = r.starts[r.rng.Intn(len(r.starts))]
+	r.vel = [2]int{0, 0}
+	return r.state()
+}
+
+// accelerations enumerates the nine actions as (right, up) increments.
+var accelerations = [][2]int{
+	{-1, -1}, {0, -1}, {1, -1},
+	{-1, 0}, {0, 0}, {1, 0},
+	{-1, 1}, {0, 1}, {1, 1},
+}
+
+func clampSpeed(v int) int {
+	if v < 0 {
+		return 0
+	}
+	if v > maxSpeed {
+		return maxSpeed
+	}
+	return v
+}
+
+func (r *Racetrack) step(action int) (State, float64, bool) {
+	acceleration := accelerations[action]
+	if r.rng.Float64() < r.noise {
+		acceleration = [2]int{0, 0}
+	}
+	vel := [2]int{clampSpeed(r.vel[0] + acceleration[0]), clampSpeed(r.vel[1] + acceleration[1])}
+	if vel[0] == 0 && vel[1] == 0 && r.blockAt(r.pos[0], r.pos[1]) != StartLine {
+		vel = r.vel
+	}
+	r.vel = vel
+
+	// Walk the projected path one cell at a time so that crossing the finish
+	// line or leaving the track is detected even at full speed.
+	steps := r.vel[0]
+	if r.vel[1] > steps {
+		steps = r.vel[1]
+	}
+	for i := 1; i <= steps; i++ {
+		x := r.pos[0] + (r.vel[0]*i+steps/2)/steps
+		y := r.pos[1] - (r.vel[1]*i+steps/2)/steps
+		switch r.blockAt(x, y) {
+		case FinishLine:
+			r.pos = [2]int{x, y}
+			return r.state(), -1.0, true
+		case Wall:
+			return r.reset(), -1.0, false
+		}
+	}
+	r.pos = [2]int{r.pos[0] + r.vel[0], r.pos[1] - r.vel[1]}
+	return r.state(), -1.0, false
+}
+
+var actions = []int{0, 1, 2, 3, 4, 5, 6, 7, 8}
+
+type Policy map[State]map[int]float64
+
+type Agent struct {
+	gamma   float64
+	policy  Policy
+	memory  []Memory
+	epsilon float64
+	alpha   learningrate.Rate
+	visits  map[State]map[int]int
+	steps   int
+	q       map[State]map[int]float64
+	rng     *rand.Rand
+}
+
+type Memory struct {
+	state  State
+	action int
+	reward float64
+}
+
+func newAgent(gamma float64, epsilon float64, alpha learningrate.Rate, rng *rand.Rand) *Agent {
+	return &Agent{
+		gamma:   gamma,
+		policy:  make(Policy),
+		memory:  make([]Memory, 0),
+		epsilon: epsilon,
+		alpha:   alpha,
+		visits:  make(map[State]map[int]int),
+		q:       make(map[State]map[int]float64),
+		rng:     rng,
+	}
+}
+
+func (a *Agent) ensureState(state State) {
+	if _, ok := a.q[state]; ok {
+		return
+	}
+	a.q[state] = make(map[int]float64)
+	a.visits[state] = make(map[int]int)
+	a.policy[state] = make(map[int]float64)
+	for _, action := range actions {
+		a.q[state][action] = 0.0
+		a.visits[state][action] = 0
+		a.policy[state][action] = 1.0 / float64(len(actions))
+	}
+}
+
+func (a *Agent) addMemory(state State, action int, reward float64) {
+	a.memory = append(a.memory, Memory{state, action, reward})
+}
+
+func (a *Agent) getAction(state State) (int, error) {
+	a.ensureState(state)
+	statePolicy := a.policy[state]
+	cumProb := 0.0
+	sample := a.rng.Float64()
+	for _, action := range actions {
+		cumProb += statePolicy[action]
+		if sample < cumProb {
+			return action, nil
+		}
+	}
+	return -1, errors.New("action not found")
+}
+
+func (a *Agent) greedyProbs(state State) map[int]float64 {
+	stateQ := a.q[state]
+	maxActions := explorer.ArgmaxAll(stateQ)
+	baseProb := a.epsilon / float64(len(actions))
+	greedyProb := (1.0 - a.epsilon) / float64(len(maxActions))
+	actionProbs := make(map[int]float64)
+	for _, action := range actions {
+		actionProbs[action] = baseProb
+	}
+	for _, action := range maxActions {
+		actionProbs[action] += greedyProb
+	}
+	return actionProbs
+}
+
+func (a *Agent) stepSize(state State, action int) float64 {
+	a.visits[state][action]++
+	a.steps++
+	return a.alpha.Alpha(a.visits[state][action], a.steps)
+}
+
+func (a *Agent) updatePolicy() {
+	g := 0.0
+	for i := len(a.memory) - 1; i >= 0; i-- {
+		memory := a.memory[i]
+		g = a.gamma*g + memory.reward
+		a.q[memory.state][memory.action] += (g - a.q[memory.state][memory.action]) * a.stepSize(memory.state, memory.action)
+		a.policy[memory.state] = a.greedyProbs(memory.state)
+	}
+}
+
+func (a *Agent) reset() {
+	a.memory = make([]Memory, 0)
+}
+
+func iterEpisodes(episodes int, agent *Agent, env *Racetrack) []int {
+	lengths := make([]int, 0, episodes)
+	for i := 0; i < episodes; i++ {
+		state := env.reset()
+		agent.reset()
+		for {
+			action, err := agent.getAction(state)
+			if err != nil {
+				panic(err)
+			}
+			nextState, reward, done := env.step(action)
+			agent.addMemory(state, action, reward)
+			if done {
+				agent.updatePolicy()
+				break
+			}
+			state = nextState
+		}
+		lengths = append(lengths, len(agent.memory))
+	}
+	return lengths
+}
+
+func main() {
+	fmt.Println("=========================================")
+	fmt.Println("Racetrack:")
+	for _, row := range trackMap {
+		fmt.Println(row)
+	}
+	fmt.Println("=========================================")
+	printTrackConf()
+	fmt.Println("=========================================")
+	rng := rand.New(rand.NewSource(0))
+	env := composeTrack(trackMap, 0.1, rng)
+	agent := newAgent(1.0, 0.1, learningrate.Harmonic{}, rng)
+	lengths := iterEpisodes(20000, agent, env)
+	for i := 0; i < len(lengths); i += 2000 {
+		sum := 0
+		for _, length := range lengths[i : i+2000] {
+			sum += length
+		}
+		fmt.Printf("episodes %5d-%5d: %.1f steps\n", i+1, i+2000, float64(sum)/2000.0)
+	}
+}
@@ -6,6 +6,10 @@ import (
 	"math/rand"
 
 	"github.com/marubontan/go-maze/maze"
+	"reinforcement-learning-playground/explorer"
+	"reinforcement-learning-playground/gridworld"
+	"reinforcement-learning-playground/learningrate"
+	"reinforcement-learning-playground/schedule"
 )
 
 func composeDungen() *maze.Maze {
@@ -33,13 +37,6 @@ func printDungenConf() {
 	fmt.Println(("G: Goal with Reward 1"))
 }
 
-const (
-	Left = iota
-	Right
-	Up
-	Down
-)
-
 type State [][2]int
 
 func getStates(m *maze.Maze) State {
@@ -52,17 +49,16 @@ func getStates(m *maze.Maze) State {
 	return blockIndices
 }
 
-type Policy map[[2]int]map[int]float64
-
-var actions = []int{Left, Right, Up, Down}
-
 type Agent struct {
-	gamma   float64
-	policy  Policy
-	memory  []Memory
-	epsilon float64
-	alpha   float64
-	q       map[[2]int]map[int]float64
+	gamma    float64
+	memory   []Memory
+	explorer explorer.Explorer[[2]int]
+	alpha    learningrate.Rate
+	visits   map[[2]int]map[int]int
+	steps    int
+	q        map[[2]int]map[int]float64
+	actions  []int
+	rng      *rand.Rand
 }
 
 type Memory struct {
@@ -71,137 +67,98 @@ type Memory struct {
 	reward float64
 }
 
-func newAgent(gamma float64, policy Policy, epsilon float64, alpha float64, states [][2]int) *Agent {
+func newAgent(gamma float64, explorer explorer.Explorer[[2]int], alpha learningrate.Rate, states [][2]int, actions []int, rng *rand.Rand) *Agent {
 	q := make(map[[2]int]map[int]float64)
+	visits := make(map[[2]int]map[int]int)
 	for _, state := range states {
 		q[state] = make(map[int]float64)
+		visits[state] = make(map[int]int)
 		for _, action := range actions {
-			q[state][action] = 0.0
+			q[state][action] = explorer.InitialQ()
+			visits[state][action] = 0
 		}
 	}
 
 	return &Agent{
-		gamma:   gamma,
-		policy:  policy,
-		memory:  make([]Memory, 0),
-		epsilon: epsilon,
-		alpha:   alpha,
-		q:       q,
+		gamma:    gamma,
+		memory:   make([]Memory, 0),
+		explorer: explorer,
+		alpha:    alpha,
+		visits:   visits,
+		q:        q,
+		actions:  actions,
+		rng:      rng,
 	}
 }
 
 func (a *Agent) addMemory(state [2]int, action int, reward float64) {
 	a.memory = append(a.memory, Memory{state, action, reward})
 }
+
+// getAction samples from the explorer's current distribution over q, so a
+// decaying schedule reaches every state, not just the ones updated since.
 func (a *Agent) getAction(state [2]int) (int, error) {
-	statePolicy := a.policy[state]
+	statePolicy := a.explorer.Probs(state, a.q[state])
 	cumProb := 0.0
-	for action, prob := range statePolicy {
-		cumProb += prob
-		if rand.Float64() < cumProb {
+	sample := a.rng.Float64()
+	for _, action := range a.actions {
+		cumProb += statePolicy[action]
+		if sample < cumProb {
+			a.explorer.Observe(state, action)
 			return action, nil
 		}
 	}
 	return -1, errors.New("action not found")
 }
 
-func argmax(data map[int]float64) int {
-	var maxKey int
-	var maxValue float64
-
-	for key, value := range data {
-		maxKey = key
-		maxValue = value
-		break
-	}
-
-	for key, value := range data {
-		if value > maxValue {
-			maxKey = key
-			maxValue = value
-		}
-	}
+func (a *Agent) stepSize(state [2]int, action int) float64 {
+	a.visits[state][action]++
+	a.steps++
+	return a.alpha.Alpha(a.visitCount(state, action), a.steps)
+}
 
-	return maxKey
+func (a *Agent) visitCount(state [2]int, action int) int {
+	return a.visits[state][action]
 }
 
-func (a *Agent) greedyProbs(state [2]int) map[int]float64 {
-	stateQ := a.q[state]
-	maxAction := argmax(stateQ)
-	baseProb := a.epsilon / float64(len(actions))
-	actionProbs := make(map[int]float64)
-	for _, action := range actions {
-		if action == maxAction {
-			actionProbs[action] = 1.0 - a.epsilon + baseProb
-		} else {
-			actionProbs[action] = baseProb
-		}
+// stateValue is the expected action value of state under the current
+// policy, used to bootstrap the return of a truncated episode.
+func (a *Agent) stateValue(state [2]int) float64 {
+	v := 0.0
+	statePolicy := a.explorer.Probs(state, a.q[state])
+	for _, action := range a.actions {
+		v += statePolicy[action] * a.q[state][action]
 	}
-	return actionProbs
+	return v
 }
 
-func (a *Agent) updatePolicy() {
-	g := 0.0
+// updatePolicy starts the backward return from bootstrap, which is zero when
+// the episode reached the goal.
+func (a *Agent) updatePolicy(bootstrap float64) {
+	g := bootstrap
 	for i := len(a.memory) - 1; i >= 0; i-- {
 		memory := a.memory[i]
 		g = a.gamma*g + memory.reward
-		a.q[memory.state][memory.action] += (g - a.q[memory.state][memory.action]) * a.alpha
-		a.policy[memory.state] = a.greedyProbs(memory.state)
+		a.q[memory.state][memory.action] += (g - a.q[memory.state][memory.action]) * a.stepSize(memory.state, memory.action)
 	}
 }
 
-func (a *Agent) step(state [2]int, action int, m *maze.Maze) ([2]int, float64, bool) {
-	goalX, goalY, err := m.GetGoal()
+func (a *Agent) step(state [2]int, action int, t int, maxSteps int, w *gridworld.World) ([2]int, float64, bool, bool) {
+	goalX, goalY, err := w.Maze.GetGoal()
 	if err != nil {
 		panic(err)
 	}
-	nextState := getNextState(state, action, m)
+	nextState := w.NextState(state, action)
 	reward := getReward(nextState, goalX, goalY)
 	isGoal := nextState[0] == goalX && nextState[1] == goalY
-	return nextState, reward, isGoal
-
+	truncated := !isGoal && t >= maxSteps
+	return nextState, reward, isGoal, truncated
 }
 
 func (a *Agent) reset() {
 	a.memory = make([]Memory, 0)
 }
 
-func newPolicy(m *maze.Maze) Policy {
-	states := getStates(m)
-	var policy Policy = make(map[[2]int]map[int]float64)
-	for _, state := range states {
-		statePolicy := make(map[int]float64)
-		for _, action := range actions {
-			statePolicy[action] = 0.25
-		}
-		policy[state] = statePolicy
-	}
-	return policy
-
-}
-
-func getNextState(state [2]int, action int, m *maze.Maze) [2]int {
-	var nextStateCandidate [2]int
-	nextStateCandidate[0] = state[0]
-	nextStateCandidate[1] = state[1]
-	switch action {
-	case Left:
-		nextStateCandidate[0]--
-	case Right:
-		nextStateCandidate[0]++
-	case Up:
-		nextStateCandidate[1]--
-	case Down:
-		nextStateCandidate[1]++
-	}
-	if m.IsAvailable(nextStateCandidate[0], nextStateCandidate[1]) {
-		return nextStateCandidate
-
-	}
-	return state
-
-}
-
 func getReward(state [2]int, goalX, goalY int) float64 {
 	if state[0] == goalX && state[1] == goalY {
 		return 1.0
@@ -212,20 +169,26 @@ func getReward(state [2]int, goalX, goalY int) float64 {
 	return 0
 }
 
-func iterEpisodes(episodes int, agent *Agent, dungeon *maze.Maze) {
-	states := getStates(dungeon)
+func iterEpisodes(episodes int, maxSteps int, agent *Agent, world *gridworld.World) {
+	states := getStates(world.Maze)
 	for i := 0; i < episodes; i++ {
 		state := states[0]
 		agent.reset()
-		for {
+		for t := 1; ; t++ {
 			action, err := agent.getAction(state)
 			if err != nil {
 				panic(err)
 			}
-			nextState, reward, isGoal := agent.step(state, action, dungeon)
+			nextState, reward, isGoal, truncated := agent.step(state, action, t, maxSteps, world)
 			agent.addMemory(state, action, reward)
 			if isGoal {
-				agent.updatePolicy()
+				agent.updatePolicy(0.0)
+				agent.explorer.Step()
+				break
+			}
+			if truncated {
+				agent.updatePolicy(agent.stateValue(nextState))
+				agent.explorer.Step()
 				break
 			}
 			state = nextState
@@ -242,9 +205,11 @@ func main() {
 	fmt.Println("=========================================")
 	printDungenConf()
 	fmt.Println("=========================================")
-	policy := newPolicy(dungeon)
+	world := gridworld.New(dungeon, gridworld.FourWay)
 	states := getStates(dungeon)
-	agent := newAgent(0.9, policy, 0.05, 0.1, states)
-	iterEpisodes(1000, agent, dungeon)
+	strategy := explorer.NewEpsilonGreedy[[2]int](schedule.NewLinear(0.5, 0.05, 500))
+	rng := rand.New(rand.NewSource(0))
+	agent := newAgent(0.9, strategy, learningrate.Harmonic{}, states, world.Actions(), rng)
+	iterEpisodes(1000, 100, agent, world)
 	fmt.Println(agent.q)
 }
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/marubontan/go-maze/maze"
+	"reinforcement-learning-playground/explorer"
+	"reinforcement-learning-playground/gridworld"
+	"reinforcement-learning-playground/learningrate"
+	"reinforcement-learning-playground/schedule"
+)
+
+const (
+	left  = 0
+	right = 1
+)
+
+// runOneStep plays a single greedy episode of at most one step from [0 0] in
+// a corridor whose goal is [1 0]. Moving left hits the wall and is truncated,
+// moving right reaches the goal. The goal's action values are nonzero so a
+// return that wrongly bootstraps from it shows up.
+func runOneStep(t *testing.T, q map[int]float64) *Agent {
+	t.Helper()
+	dungeon := maze.NewMaze(1, 2)
+	if err := dungeon.SetStart(0, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := dungeon.SetGoal(1, 0); err != nil {
+		t.Fatal(err)
+	}
+	world := gridworld.New(dungeon, gridworld.FourWay)
+	strategy := explorer.NewEpsilonGreedy[[2]int](schedule.Constant(0))
+	rng := rand.New(rand.NewSource(0))
+	agent := newAgent(0.9, strategy, learningrate.Constant(1.0), getStates(dungeon), world.Actions(), rng)
+	for action, value := range q {
+		agent.q[[2]int{0, 0}][action] = value
+	}
+	for action := range agent.q[[2]int{1, 0}] {
+		agent.q[[2]int{1, 0}][action] = 3.0
+	}
+	iterEpisodes(1, 1, agent, world)
+	return agent
+}
+
+func TestTruncatedReturnBootstraps(t *testing.T) {
+	agent := runOneStep(t, map[int]float64{left: 0.5})
+	// The wall keeps the agent at [0 0], whose value under the greedy policy
+	// was 0.5 when the episode was cut off.
+	want := 0.0 + 0.9*0.5
+	if got := agent.q[[2]int{0, 0}][left]; math.Abs(got-want) > 1e-9 {
+		t.Fatalf("q([0 0], Left) = %v, want %v", got, want)
+	}
+}
+
+func TestTerminatedReturnDoesNotBootstrap(t *testing.T) {
+	agent := runOneStep(t, map[int]float64{right: 2.0})
+	if got := agent.q[[2]int{0, 0}][right]; math.Abs(got-1.0) > 1e-9 {
+		t.Fatalf("q([0 0], Right) = %v, want the goal reward 1", got)
+	}
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+
+	"reinforcement-learning-playground/nn"
+)
+
+func main() {
+	rng := rand.New(rand.NewSource(0))
+	net := nn.NewMLP([]int{1, 16, 1}, nn.Tanh, rng)
+	optimizer := nn.NewAdam(net.Params(), 0.01)
+	inputs := make([][]float64, 32)
+	outputs := make([][]float64, 32)
+	for i := range inputs {
+		v := -3.0 + 6.0*float64(i)/31.0
+		inputs[i] = []float64{v}
+		outputs[i] = []float64{v * v / 9.0}
+	}
+	xs := nn.FromRows(inputs)
+	ys := nn.FromRows(outputs)
+	fmt.Println("=========================================")
+	for epoch := 0; epoch <= 2000; epoch++ {
+		optimizer.ZeroGrad()
+		loss := nn.MSE(net.Forward(xs), ys)
+		loss.Backward()
+		optimizer.Step()
+		if epoch%500 == 0 {
+			fmt.Printf("Fitting x^2/9 with Adam, epoch %4d: mse %.5f\n", epoch, loss.Item())
+		}
+	}
+	fmt.Println("=========================================")
+}
@@ -0,0 +1,340 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/marubontan/go-maze/maze"
+	"reinforcement-learning-playground/classiccontrol"
+	"reinforcement-learning-playground/gridworld"
+	"reinforcement-learning-playground/nn"
+)
+
+func composeDungen() *maze.Maze {
+	dungeon := maze.NewMaze(3, 4)
+	var err error
+	err = dungeon.SetStart(0, 2)
+	if err != nil {
+		panic(err)
+	}
+	err = dungeon.SetGoal(3, 0)
+	if err != nil {
+		panic(err)
+	}
+	err = dungeon.SetObstacle(1, 1)
+	if err != nil {
+		panic(err)
+	}
+	return dungeon
+}
+
+func printDungenConf() {
+	fmt.Println("Dungeon Configuration:")
+	fmt.Println("S: Start Position")
+	fmt.Println("X: Obstacle")
+	fmt.Println(("G: Goal with Reward 1"))
+}
+
+func getReward(state [2]int, goalX, goalY int) float64 {
+	if state[0] == goalX && state[1] == goalY {
+		return 1.0
+	}
+	if state[0] == 3 && state[1] == 1 {
+		return -1.0
+	}
+	return 0
+}
+
+type Agent struct {
+	gamma       float64
+	lambda      float64
+	clip        float64
+	horizon     int
+	epochs      int
+	minibatch   int
+	valueCoef   float64
+	entropyCoef float64
+	actor       nn.Sequential
+	critic      nn.Sequential
+	params      []*nn.Tensor
+	optimizer   nn.Optimizer
+	memory      []Memory
+	actions     []int
+	rng         *rand.Rand
+}
+
+type Memory struct {
+	observation []float64
+	action      int
+	reward      float64
+	isGoal      bool
+	truncated   bool
+	logProb     float64
+	value       float64
+	nextValue   float64
+}
+
+func newAgent(gamma float64, lambda float64, clip float64, horizon int, epochs int, minibatch int, lr float64, valueCoef float64, entropyCoef float64, sizes []int, actions []int, rng *rand.Rand) *Agent {
+	actorSizes := append(append([]int{}, sizes...), len(actions))
+	criticSizes := append(append([]int{}, sizes...), 1)
+	actor := nn.NewMLP(actorSizes, nn.Tanh, rng)
+	critic := nn.NewMLP(criticSizes, nn.Tanh, rng)
+	params := append(actor.Params(), critic.Params()...)
+	return &Agent{
+		gamma:       gamma,
+		lambda:      lambda,
+		clip:        clip,
+		horizon:     horizon,
+		epochs:      epochs,
+		minibatch:   minibatch,
+		valueCoef:   valueCoef,
+		entropyCoef: entropyCoef,
+		actor:       actor,
+		critic:      critic,
+		params:      params,
+		optimizer:   nn.NewAdam(params, lr),
+		memory:      make([]Memory, 0, horizon),
+		actions:     actions,
+		rng:         rng,
+	}
+}
+
+func (a *Agent) probs(observation []float64) []float64 {
+	logits := a.actor.Forward(nn.New(1, len(observation), observation))
+	return nn.Exp(nn.LogSoftmax(logits)).Data
+}
+
+func (a *Agent) value(observation []float64) float64 {
+	return a.critic.Forward(nn.New(1, len(observation), observation)).Item()
+}
+
+func (a *Agent) getAction(observation []float64) (int, error) {
+	statePolicy := a.probs(observation)
+	cumProb := 0.0
+	sample := a.rng.Float64()
+	for _, action := range a.actions {
+		cumProb += statePolicy[action]
+		if sample < cumProb {
+			return action, nil
+		}
+	}
+	return -1, errors.New("action not found")
+}
+
+// update records the transition together with the log probability and
+// value under the networks that chose it, and trains once horizon steps
+// have been collected, possibly spanning several episodes. A truncated
+// transition also keeps the value of the observation it was cut off at.
+func (a *Agent) update(observation []float64, action int, reward float64, nextObservation []float64, isGoal bool, truncated bool) {
+	memory := Memory{
+		observation: observation,
+		action:      action,
+		reward:      reward,
+		isGoal:      isGoal,
+		truncated:   truncated,
+		logProb:     math.Log(a.probs(observation)[action]),
+		value:       a.value(observation),
+	}
+	if truncated {
+		memory.nextValue = a.value(nextObservation)
+	}
+	a.memory = append(a.memory, memory)
+	if len(a.memory) == a.horizon {
+		a.learn(nextObservation)
+		a.memory = a.memory[:0]
+	}
+}
+
+// advantages computes generalized advantage estimates by the backward
+// recursion A_t = delta_t + gamma lambda A_{t+1}, cut at episode ends. A
+// terminated step has no next value, a truncated one bootstraps from the
+// value stored with it.
+func (a *Agent) advantages(nextObservation []float64) ([]float64, []float64) {
+	size := len(a.memory)
+	advantages := make([]float64, size)
+	returns := make([]float64, size)
+	nextValue := a.value(nextObservation)
+	advantage := 0.0
+	for i := size - 1; i >= 0; i-- {
+		memory := a.memory[i]
+		if memory.isGoal {
+			nextValue = 0.0
+			advantage = 0.0
+		} else if memory.truncated {
+			nextValue = memory.nextValue
+			advantage = 0.0
+		}
+		delta := memory.reward + a.gamma*nextValue - memory.value
+		advantage = delta + a.gamma*a.lambda*advantage
+		advantages[i] = advantage
+		returns[i] = advantage + memory.value
+		nextValue = memory.value
+	}
+	return advantages, returns
+}
+
+func normalize(data []float64) {
+	mean := 0.0
+	for _, x := range data {
+		mean += x
+	}
+	mean /= float64(len(data))
+	variance := 0.0
+	for _, x := range data {
+		variance += (x - mean) * (x - mean)
+	}
+	std := math.Sqrt(variance/float64(len(data))) + 1e-8
+	for i := range data {
+		data[i] = (data[i] - mean) / std
+	}
+}
+
+func (a *Agent) learn(nextObservation []float64) {
+	advantages, returns := a.advantages(nextObservation)
+	normalize(advantages)
+	for epoch := 0; epoch < a.epochs; epoch++ {
+		order := a.rng.Perm(len(a.memory))
+		for start := 0; start < len(order); start += a.minibatch {
+			end := start + a.minibatch
+			if end > len(order) {
+				end = len(order)
+			}
+			a.learnMinibatch(order[start:end], advantages, returns)
+		}
+	}
+}
+
+// learnMinibatch minimizes the negated clipped surrogate, the larger of the
+// unclipped and clipped value errors, and the negated entropy.
+func (a *Agent) learnMinibatch(indices []int, advantages []float64, returns []float64) {
+	size := len(indices)
+	observations := make([][]float64, size)
+	actions := make([]int, size)
+	oldLogProbs := nn.Zeros(size, 1)
+	oldValues := nn.Zeros(size, 1)
+	batchAdvantages := nn.Zeros(size, 1)
+	batchReturns := nn.Zeros(size, 1)
+	for i, index := range indices {
+		memory := a.memory[index]
+		observations[i] = memory.observation
+		actions[i] = memory.action
+		oldLogProbs.Data[i] = memory.logProb
+		oldValues.Data[i] = memory.value
+		batchAdvantages.Data[i] = advantages[index]
+		batchReturns.Data[i] = returns[index]
+	}
+	states := nn.FromRows(observations)
+
+	logProbs := nn.LogSoftmax(a.actor.Forward(states))
+	ratio := nn.Exp(nn.Sub(nn.Gather(logProbs, actions), oldLogProbs))
+	surrogate := nn.Mul(ratio, batchAdvantages)
+	clippedSurrogate := nn.Mul(nn.Clip(ratio, 1-a.clip, 1+a.clip), batchAdvantages)
+	policyLoss := nn.Scale(nn.Mean(nn.Min(surrogate, clippedSurrogate)), -1.0)
+
+	values := a.critic.Forward(states)
+	clippedValues := nn.Add(oldValues, nn.Clip(nn.Sub(values, oldValues), -a.clip, a.clip))
+	valueError := nn.Sub(values, batchReturns)
+	clippedValueError := nn.Sub(clippedValues, batchReturns)
+	valueLoss := nn.Scale(nn.Mean(nn.Max(nn.Mul(valueError, valueError), nn.Mul(clippedValueError, clippedValueError))), 0.5)
+
+	negEntropy := nn.Scale(nn.Sum(nn.Mul(nn.Exp(logProbs), logProbs)), 1.0/float64(size))
+	loss := nn.Add(nn.Add(policyLoss, nn.Scale(valueLoss, a.valueCoef)), nn.Scale(negEntropy, a.entropyCoef))
+	a.optimizer.ZeroGrad()
+	loss.Backward()
+	nn.ClipGradNorm(a.params, 0.5)
+	a.optimizer.Step()
+}
+
+func iterEpisodes(episodes int, agent *Agent, env classiccontrol.Env) []float64 {
+	returns := make([]float64, 0, episodes)
+	for i := 0; i < episodes; i++ {
+		observation := env.Reset()
+		g := 0.0
+		for {
+			action, err := agent.getAction(observation)
+			if err != nil {
+				panic(err)
+			}
+			nextObservation, reward, isGoal, truncated := env.Step(action)
+			agent.update(observation, action, reward, nextObservation, isGoal, truncated)
+			g += reward
+			if isGoal || truncated {
+				break
+			}
+			observation = nextObservation
+		}
+		returns = append(returns, g)
+	}
+	return returns
+}
+
+func evaluate(episodes int, agent *Agent, env classiccontrol.Env) float64 {
+	sum := 0.0
+	for i := 0; i < episodes; i++ {
+		observation := env.Reset()
+		for {
+			statePolicy := agent.probs(observation)
+			action := agent.actions[0]
+			for _, candidate := range agent.actions {
+				if statePolicy[candidate] > statePolicy[action] {
+					action = candidate
+				}
+			}
+			nextObservation, reward, isGoal, truncated := env.Step(action)
+			sum += reward
+			if isGoal || truncated {
+				break
+			}
+			observation = nextObservation
+		}
+	}
+	return sum / float64(episodes)
+}
+
+func printReturns(returns []float64, window int) {
+	for i := 0; i+window <= len(returns); i += window {
+		sum := 0.0
+		for _, g := range returns[i : i+window] {
+			sum += g
+		}
+		fmt.Printf("episodes %4d-%4d: %.1f\n", i+1, i+window, sum/float64(window))
+	}
+}
+
+func main() {
+	dungeon := composeDungen()
+	fmt.Println("=========================================")
+	fmt.Println("Dungeon:")
+	dungeon.Print()
+	fmt.Println("=========================================")
+	printDungenConf()
+	fmt.Println("=========================================")
+	world := gridworld.New(dungeon, gridworld.FourWay)
+	rng := rand.New(rand.NewSource(0))
+	gridEnv := gridworld.NewEnv(world, getReward, [2]int{0, 0}, 100)
+	gridAgent := newAgent(0.9, 0.95, 0.2, 128, 4, 32, 3e-3, 0.5, 0.01, []int{gridEnv.Dim(), 32}, world.Actions(), rng)
+	iterEpisodes(500, gridAgent, gridEnv)
+	fmt.Printf("Gridworld greedy return: %.1f\n", evaluate(1, gridAgent, gridEnv))
+	v := make(map[[2]int]float64)
+	for hI, hBlocks := range dungeon.Blocks {
+		for wI := range hBlocks {
+			state := [2]int{wI, hI}
+			v[state] = gridAgent.value(gridEnv.Observation(state))
+		}
+	}
+	fmt.Println(v)
+	fmt.Println("=========================================")
+
+	start := time.Now()
+	cartPole := classiccontrol.NewCartPole(rng)
+	cartAgent := newAgent(0.99, 0.95, 0.2, 512, 10, 64, 3e-4, 0.5, 0.0, []int{4, 64}, cartPole.Actions(), rng)
+	returns := iterEpisodes(500, cartAgent, cartPole)
+	fmt.Println("CartPole mean return per 50 episodes:")
+	printReturns(returns, 50)
+	fmt.Printf("trained for 500 episodes in %s\n", time.Since(start).Round(time.Second))
+	fmt.Printf("greedy mean return over 10 episodes: %.1f\n", evaluate(10, cartAgent, cartPole))
+	fmt.Println("=========================================")
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/marubontan/go-maze/maze"
+	"reinforcement-learning-playground/approx"
+	"reinforcement-learning-playground/gridworld"
+)
+
+func composeDungen() *maze.Maze {
+	dungeon := maze.NewMaze(3, 4)
+	var err error
+	err = dungeon.SetStart(0, 2)
+	if err != nil {
+		panic(err)
+	}
+	err = dungeon.SetGoal(3, 0)
+	if err != nil {
+		panic(err)
+	}
+	err = dungeon.SetObstacle(1, 1)
+	if err != nil {
+		panic(err)
+	}
+	return dungeon
+}
+
+func printDungenConf() {
+	fmt.Println("Dungeon Configuration:")
+	fmt.Println("S: Start Position")
+	fmt.Println("X: Obstacle")
+	fmt.Println(("G: Goal with Reward 1"))
+}
+
+type State [][2]int
+
+func getStates(m *maze.Maze) State {
+	blockIndices := make(State, 0)
+	for hI, hBlocks := range m.Blocks {
+		for wI := range hBlocks {
+			blockIndices = append(blockIndices, [2]int{wI, hI})
+		}
+	}
+	return blockIndices
+}
+
+type Agent struct {
+	gamma      float64
+	alphaTheta float64
+	alphaW     float64
+	policy     *approx.SoftmaxPolicy
+	baseline   *approx.LinearV
+	memory     []Memory
+	actions    []int
+	rng        *rand.Rand
+}
+
+type Memory struct {
+	state  [2]int
+	action int
+	reward float64
+}
+
+// newAgent learns without a baseline when baseline is nil.
+func newAgent(gamma float64, alphaTheta float64, alphaW float64, policy *approx.SoftmaxPolicy, baseline *approx.LinearV, actions []int, rng *rand.Rand) *Agent {
+	return &Agent{
+		gamma:      gamma,
+		alphaTheta: alphaTheta,
+		alphaW:     alphaW,
+		policy:     policy,
+		baseline:   baseline,
+		memory:     make([]Memory, 0),
+		actions:    actions,
+		rng:        rng,
+	}
+}
+
+func (a *Agent) addMemory(state [2]int, action int, reward float64) {
+	a.memory = append(a.memory, Memory{state, action, reward})
+}
+
+func (a *Agent) getAction(state [2]int) (int, error) {
+	statePolicy := a.policy.Probs(state)
+	cumProb := 0.0
+	sample := a.rng.Float64()
+	for _, action := range a.actions {
+		cumProb += statePolicy[action]
+		if sample < cumProb {
+			return action, nil
+		}
+	}
+	return -1, errors.New("action not found")
+}
+
+// updatePolicy walks the episode backwards accumulating the return G_t and
+// steps theta along gamma^t (G_t - b(S_t)) grad ln pi(A_t|S_t). The return
+// starts from bootstrap, which is zero unless the episode was truncated.
+func (a *Agent) updatePolicy(bootstrap float64) {
+	g := bootstrap
+	for i := len(a.memory) - 1; i >= 0; i-- {
+		memory := a.memory[i]
+		g = a.gamma*g + memory.reward
+		delta := g
+		if a.baseline != nil {
+			delta -= a.baseline.Value(memory.state)
+			a.baseline.Update(memory.state, a.alphaW, delta)
+		}
+		a.policy.Update(memory.state, memory.action, a.alphaTheta, math.Pow(a.gamma, float64(i))*delta)
+	}
+}
+
+func (a *Agent) step(state [2]int, action int, t int, maxSteps int, w *gridworld.World) ([2]int, float64, bool, bool) {
+	goalX, goalY, err := w.Maze.GetGoal()
+	if err != nil {
+		panic(err)
+	}
+	nextState := w.NextState(state, action)
+	reward := getReward(nextState, goalX, goalY)
+	isGoal := nextState[0] == goalX && nextState[1] == goalY
+	truncated := !isGoal && t >= maxSteps
+	return nextState, reward, isGoal, truncated
+}
+
+func (a *Agent) reset() {
+	a.memory = make([]Memory, 0)
+}
+
+func getReward(state [2]int, goalX, goalY int) float64 {
+	if state[0] == goalX && state[1] == goalY {
+		return 1.0
+	}
+	if state[0] == 3 && state[1] == 1 {
+		return -1.0
+	}
+	return 0
+}
+
+// iterEpisodes cuts every episode off after maxSteps. A truncated return is
+// completed with the baseline's estimate of the state it was cut off at, or
+// left as it is without a baseline.
+func iterEpisodes(episodes int, maxSteps int, agent *Agent, world *gridworld.World) []int {
+	states := getStates(world.Maze)
+	lengths := make([]int, 0, episodes)
+	for i := 0; i < episodes; i++ {
+		state := states[0]
+		agent.reset()
+		for t := 1; ; t++ {
+			action, err := agent.getAction(state)
+			if err != nil {
+				panic(err)
+			}
+			nextState, reward, isGoal, truncated := agent.step(state, action, t, maxSteps, world)
+			agent.addMemory(state, action, reward)
+			if isGoal {
+				agent.updatePolicy(0.0)
+				break
+			}
+			if truncated {
+				bootstrap := 0.0
+				if agent.baseline != nil {
+					bootstrap = agent.baseline.Value(nextState)
+				}
+				agent.updatePolicy(bootstrap)
+				break
+			}
+			state = nextState
+		}
+		lengths = append(lengths, len(agent.memory))
+	}
+	return lengths
+}
+
+func main() {
+	dungeon := composeDungen()
+	fmt.Println("=========================================")
+	fmt.Println("Dungeon:")
+	dungeon.Print()
+	fmt.Println("=========================================")
+	printDungenConf()
+	fmt.Println("=========================================")
+	world := gridworld.New(dungeon, gridworld.FourWay)
+	oneHot := approx.NewOneHot(dungeon.Width, dungeon.Height)
+	rbf := approx.NewRadialBasis(dungeon.Width, dungeon.Height, 4, 3, 0.3)
+	configs := []struct {
+		name     string
+		features approx.Features
+		baseline bool
+	}{
+		{"tabular", oneHot, false},
+		{"tabular+baseline", oneHot, true},
+		{"linear rbf", rbf, false},
+		{"linear rbf+baseline", rbf, true},
+	}
+	var agent *Agent
+	for _, config := range configs {
+		rng := rand.New(rand.NewSource(0))
+		policy := approx.NewSoftmaxPolicy(config.features, world.Actions())
+		var baseline *approx.LinearV
+		if config.baseline {
+			baseline = approx.NewLinearV(config.features)
+		}
+		agent = newAgent(0.9, 0.1, 0.1, policy, baseline, world.Actions(), rng)
+		lengths := iterEpisodes(2000, 100, agent, world)
+		fmt.Printf("%-20s mean episode length", config.name)
+		for i := 0; i < len(lengths); i += 500 {
+			sum := 0
+			for _, length := range lengths[i : i+500] {
+				sum += length
+			}
+			fmt.Printf(" %6.2f", float64(sum)/500.0)
+		}
+		fmt.Println()
+	}
+	fmt.Println("=========================================")
+	probs := make(map[[2]int]map[int]float64)
+	for _, state := range getStates(dungeon) {
+		probs[state] = agent.policy.Probs(state)
+	}
+	fmt.Println(probs)
+}
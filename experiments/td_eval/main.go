@@ -6,6 +6,7 @@ import (
 	"math/rand"
 
 	"github.com/marubontan/go-maze/maze"
+	"reinforcement-learning-playground/gridworld"
 )
 
 func composeDungen() *maze.Maze {
@@ -33,13 +34,6 @@ func printDungenConf() {
 	fmt.Println(("G: Goal with Reward 1"))
 }
 
-const (
-	Left = iota
-	Right
-	Up
-	Down
-)
-
 type State [][2]int
 
 func getStates(m *maze.Maze) State {
@@ -54,8 +48,6 @@ func getStates(m *maze.Maze) State {
 
 type Policy map[[2]int]map[int]float64
 
-var actions = []int{Left, Right, Up, Down}
-
 type Agent struct {
 	gamma  float64
 	policy Policy
@@ -91,16 +83,16 @@ func (a *Agent) getAction(state [2]int) (int, error) {
 	return -1, errors.New("action not found")
 }
 
-func (a *Agent) step(state [2]int, action int, m *maze.Maze) ([2]int, float64, bool) {
-	goalX, goalY, err := m.GetGoal()
+func (a *Agent) step(state [2]int, action int, t int, maxSteps int, w *gridworld.World) ([2]int, float64, bool, bool) {
+	goalX, goalY, err := w.Maze.GetGoal()
 	if err != nil {
 		panic(err)
 	}
-	nextState := getNextState(state, action, m)
+	nextState := w.NextState(state, action)
 	reward := getReward(nextState, goalX, goalY)
 	isGoal := nextState[0] == goalX && nextState[1] == goalY
-	return nextState, reward, isGoal
-
+	truncated := !isGoal && t >= maxSteps
+	return nextState, reward, isGoal, truncated
 }
 
 func (a *Agent) eval(state [2]int, reward float64, nextState [2]int, isGoal bool) {
@@ -115,13 +107,13 @@ func (a *Agent) eval(state [2]int, reward float64, nextState [2]int, isGoal bool
 
 }
 
-func newPolicy(m *maze.Maze) Policy {
-	states := getStates(m)
+func newPolicy(w *gridworld.World) Policy {
+	states := getStates(w.Maze)
 	var policy Policy = make(map[[2]int]map[int]float64)
 	for _, state := range states {
 		statePolicy := make(map[int]float64)
-		for _, action := range actions {
-			statePolicy[action] = 0.25
+		for _, action := range w.Actions() {
+			statePolicy[action] = 1.0 / float64(len(w.Actions()))
 		}
 		policy[state] = statePolicy
 	}
@@ -129,28 +121,6 @@ func newPolicy(m *maze.Maze) Policy {
 
 }
 
-func getNextState(state [2]int, action int, m *maze.Maze) [2]int {
-	var nextStateCandidate [2]int
-	nextStateCandidate[0] = state[0]
-	nextStateCandidate[1] = state[1]
-	switch action {
-	case Left:
-		nextStateCandidate[0]--
-	case Right:
-		nextStateCandidate[0]++
-	case Up:
-		nextStateCandidate[1]--
-	case Down:
-		nextStateCandidate[1]++
-	}
-	if m.IsAvailable(nextStateCandidate[0], nextStateCandidate[1]) {
-		return nextStateCandidate
-
-	}
-	return state
-
-}
-
 func getReward(state [2]int, goalX, goalY int) float64 {
 	if state[0] == goalX && state[1] == goalY {
 		return 1.0
@@ -161,18 +131,21 @@ func getReward(state [2]int, goalX, goalY int) float64 {
 	return 0
 }
 
-func iterEpisodes(episodes int, agent *Agent, dungeon *maze.Maze) {
-	states := getStates(dungeon)
+// iterEpisodes cuts every episode off after maxSteps. The last update of a
+// truncated episode still bootstraps from v(nextState) since only reaching
+// the goal ends the return.
+func iterEpisodes(episodes int, maxSteps int, agent *Agent, world *gridworld.World) {
+	states := getStates(world.Maze)
 	for i := 0; i < episodes; i++ {
 		state := states[0]
-		for {
+		for t := 1; ; t++ {
 			action, err := agent.getAction(state)
 			if err != nil {
 				panic(err)
 			}
-			nextState, reward, isGoal := agent.step(state, action, dungeon)
+			nextState, reward, isGoal, truncated := agent.step(state, action, t, maxSteps, world)
 			agent.eval(state, reward, nextState, isGoal)
-			if isGoal {
+			if isGoal || truncated {
 				break
 			}
 			state = nextState
@@ -189,9 +162,10 @@ func main() {
 	fmt.Println("=========================================")
 	printDungenConf()
 	fmt.Println("=========================================")
-	policy := newPolicy(dungeon)
+	world := gridworld.New(dungeon, gridworld.FourWay)
+	policy := newPolicy(world)
 	states := getStates(dungeon)
 	agent := newAgent(0.9, 0.9, policy, states)
-	iterEpisodes(1000, agent, dungeon)
+	iterEpisodes(1000, 100, agent, world)
 	fmt.Println(agent.v)
 }
@@ -6,6 +6,10 @@ import (
 	"math/rand"
 
 	"github.com/marubontan/go-maze/maze"
+	"reinforcement-learning-playground/explorer"
+	"reinforcement-learning-playground/gridworld"
+	"reinforcement-learning-playground/learningrate"
+	"reinforcement-learning-playground/schedule"
 )
 
 func composeDungen() *maze.Maze {
@@ -33,12 +37,39 @@ func printDungenConf() {
 	fmt.Println(("G: Goal with Reward 1"))
 }
 
-const (
-	Left = iota
-	Right
-	Up
-	Down
-)
+var wind = []int{0, 0, 0, 1, 1, 1, 2, 2, 1, 0}
+
+func composeWindy() *maze.Maze {
+	windy := maze.NewMaze(7, 10)
+	var err error
+	err = windy.SetStart(0, 3)
+	if err != nil {
+		panic(err)
+	}
+	err = windy.SetGoal(7, 3)
+	if err != nil {
+		panic(err)
+	}
+	return windy
+}
+
+func composeCliff() (*maze.Maze, [][2]int) {
+	cliff := maze.NewMaze(4, 12)
+	var err error
+	err = cliff.SetStart(0, 3)
+	if err != nil {
+		panic(err)
+	}
+	err = cliff.SetGoal(11, 3)
+	if err != nil {
+		panic(err)
+	}
+	blocks := make([][2]int, 0)
+	for x := 1; x < 11; x++ {
+		blocks = append(blocks, [2]int{x, 3})
+	}
+	return cliff, blocks
+}
 
 type State [][2]int
 
@@ -52,164 +83,91 @@ func getStates(m *maze.Maze) State {
 	return blockIndices
 }
 
-type Policy map[[2]int]map[int]float64
-
-type HistoryElement struct {
-	state  [2]int
-	action int
-	reward float64
-	isGoal bool
-}
-
-var actions = []int{Left, Right, Up, Down}
-
 type Agent struct {
-	gamma   float64
-	policy  Policy
-	alpha   float64
-	epsilon float64
-	q       map[[2]int]map[int]float64
-	memory  [2]*HistoryElement
+	gamma    float64
+	alpha    learningrate.Rate
+	visits   map[[2]int]map[int]int
+	steps    int
+	explorer explorer.Explorer[[2]int]
+	q        map[[2]int]map[int]float64
+	actions  []int
+	rng      *rand.Rand
 }
 
-func newAgent(gamma float64, alpha float64, epsilon float64, policy Policy, states [][2]int, actions []int) *Agent {
+func newAgent(gamma float64, alpha learningrate.Rate, explorer explorer.Explorer[[2]int], states [][2]int, actions []int, rng *rand.Rand) *Agent {
 	q := make(map[[2]int]map[int]float64)
+	visits := make(map[[2]int]map[int]int)
 	for _, state := range states {
 		q[state] = make(map[int]float64)
+		visits[state] = make(map[int]int)
 		for _, action := range actions {
-			q[state][action] = 0.0
+			q[state][action] = explorer.InitialQ()
+			visits[state][action] = 0
 		}
 	}
-	memory := [2]*HistoryElement{nil, nil}
-
 	return &Agent{
-		gamma:   gamma,
-		policy:  policy,
-		epsilon: epsilon,
-		alpha:   alpha,
-		q:       q,
-		memory:  memory,
+		gamma:    gamma,
+		explorer: explorer,
+		alpha:    alpha,
+		visits:   visits,
+		q:        q,
+		actions:  actions,
+		rng:      rng,
 	}
 }
 
+// getAction samples from the explorer's current distribution over q, so a
+// decaying schedule reaches every state, not just the ones updated since.
 func (a *Agent) getAction(state [2]int) (int, error) {
-	statePolicy := a.policy[state]
+	statePolicy := a.explorer.Probs(state, a.q[state])
 	cumProb := 0.0
-	for action, prob := range statePolicy {
-		cumProb += prob
-		if rand.Float64() < cumProb {
+	sample := a.rng.Float64()
+	for _, action := range a.actions {
+		cumProb += statePolicy[action]
+		if sample < cumProb {
+			a.explorer.Observe(state, action)
 			return action, nil
 		}
 	}
 	return -1, errors.New("action not found")
 }
 
-func (a *Agent) step(state [2]int, action int, m *maze.Maze) ([2]int, float64, bool) {
-	goalX, goalY, err := m.GetGoal()
+// step pays the world's cliff reward instead of getReward when the move
+// ran into the cliff.
+func (a *Agent) step(state [2]int, action int, t int, maxSteps int, w *gridworld.World, getReward gridworld.Reward) ([2]int, float64, bool, bool) {
+	goalX, goalY, err := w.Maze.GetGoal()
 	if err != nil {
 		panic(err)
 	}
-	nextState := getNextState(state, action, m)
+	nextState, fell := w.Move(state, action)
 	reward := getReward(nextState, goalX, goalY)
+	if fell {
+		reward = w.CliffReward
+	}
 	isGoal := nextState[0] == goalX && nextState[1] == goalY
-	return nextState, reward, isGoal
+	truncated := !isGoal && t >= maxSteps
+	return nextState, reward, isGoal, truncated
 }
 
-func (a *Agent) reset() {
-	a.memory[0] = nil
-	a.memory[1] = nil
+func (a *Agent) stepSize(state [2]int, action int) float64 {
+	a.visits[state][action]++
+	a.steps++
+	return a.alpha.Alpha(a.visitCount(state, action), a.steps)
 }
 
-func (a *Agent) update(state [2]int, action int, reward float64, isGoal bool) {
-	a.memory[1] = a.memory[0]
-	a.memory[0] = &HistoryElement{
-		state:  state,
-		action: action,
-		reward: reward,
-		isGoal: isGoal,
-	}
-	if a.memory[1] == nil {
-		return
-	}
+func (a *Agent) visitCount(state [2]int, action int) int {
+	return a.visits[state][action]
+}
+
+func (a *Agent) update(state [2]int, action int, reward float64, nextState [2]int, nextAction int, isGoal bool) {
 	var nextQ float64
 	if isGoal {
 		nextQ = 0.0
 	} else {
-		nextQ = a.q[a.memory[1].state][a.memory[1].action]
+		nextQ = a.q[nextState][nextAction]
 	}
 	target := reward + a.gamma*nextQ
-	a.q[state][action] += (target - a.q[state][action]) * a.alpha
-
-	a.policy[state] = a.greedyProbs(state)
-}
-
-func newPolicy(m *maze.Maze) Policy {
-	states := getStates(m)
-	var policy Policy = make(map[[2]int]map[int]float64)
-	for _, state := range states {
-		statePolicy := make(map[int]float64)
-		for _, action := range actions {
-			statePolicy[action] = 0.25
-		}
-		policy[state] = statePolicy
-	}
-	return policy
-
-}
-func argmax(data map[int]float64) int {
-	var maxKey int
-	var maxValue float64
-
-	for key, value := range data {
-		maxKey = key
-		maxValue = value
-		break
-	}
-
-	for key, value := range data {
-		if value > maxValue {
-			maxKey = key
-			maxValue = value
-		}
-	}
-
-	return maxKey
-}
-func (a *Agent) greedyProbs(state [2]int) map[int]float64 {
-	stateQ := a.q[state]
-	maxAction := argmax(stateQ)
-	baseProb := a.epsilon / float64(len(actions))
-	actionProbs := make(map[int]float64)
-	for _, action := range actions {
-		if action == maxAction {
-			actionProbs[action] = 1.0 - a.epsilon + baseProb
-		} else {
-			actionProbs[action] = baseProb
-		}
-	}
-	return actionProbs
-}
-
-func getNextState(state [2]int, action int, m *maze.Maze) [2]int {
-	var nextStateCandidate [2]int
-	nextStateCandidate[0] = state[0]
-	nextStateCandidate[1] = state[1]
-	switch action {
-	case Left:
-		nextStateCandidate[0]--
-	case Right:
-		nextStateCandidate[0]++
-	case Up:
-		nextStateCandidate[1]--
-	case Down:
-		nextStateCandidate[1]++
-	}
-	if m.IsAvailable(nextStateCandidate[0], nextStateCandidate[1]) {
-		return nextStateCandidate
-
-	}
-	return state
-
+	a.q[state][action] += (target - a.q[state][action]) * a.stepSize(state, action)
 }
 
 func getReward(state [2]int, goalX, goalY int) float64 {
@@ -222,25 +180,54 @@ func getReward(state [2]int, goalX, goalY int) float64 {
 	return 0
 }
 
-func iterEpisodes(episodes int, agent *Agent, dungeon *maze.Maze) {
-	states := getStates(dungeon)
+// getStepReward charges -1 for every move until the goal is reached.
+func getStepReward(state [2]int, goalX, goalY int) float64 {
+	return -1.0
+}
+
+// iterEpisodes starts every episode at start and cuts it off after
+// maxSteps. Only reaching the goal drops the next action value from the
+// target. It returns the undiscounted return of every episode.
+func iterEpisodes(episodes int, maxSteps int, agent *Agent, world *gridworld.World, start [2]int, getReward gridworld.Reward) []float64 {
+	returns := make([]float64, 0, episodes)
 	for i := 0; i < episodes; i++ {
-		state := states[0]
-		agent.reset()
-		for {
-			action, err := agent.getAction(state)
+		state := start
+		action, err := agent.getAction(state)
+		if err != nil {
+			panic(err)
+		}
+		g := 0.0
+		for t := 1; ; t++ {
+			nextState, reward, isGoal, truncated := agent.step(state, action, t, maxSteps, world, getReward)
+			g += reward
+			if isGoal {
+				agent.update(state, action, reward, nextState, -1, isGoal)
+				break
+			}
+			nextAction, err := agent.getAction(nextState)
 			if err != nil {
 				panic(err)
 			}
-			nextState, reward, isGoal := agent.step(state, action, dungeon)
-			agent.update(state, action, reward, isGoal)
-			if isGoal {
+			agent.update(state, action, reward, nextState, nextAction, isGoal)
+			if truncated {
 				break
 			}
 			state = nextState
+			action = nextAction
 		}
+		returns = append(returns, g)
+		agent.explorer.Step()
+	}
+	return returns
+}
 
+// meanOfLast averages the last n returns.
+func meanOfLast(returns []float64, n int) float64 {
+	sum := 0.0
+	for _, g := range returns[len(returns)-n:] {
+		sum += g
 	}
+	return sum / float64(n)
 }
 
 func main() {
@@ -251,9 +238,60 @@ func main() {
 	fmt.Println("=========================================")
 	printDungenConf()
 	fmt.Println("=========================================")
-	policy := newPolicy(dungeon)
+	world := gridworld.New(dungeon, gridworld.FourWay)
 	states := getStates(dungeon)
-	agent := newAgent(0.9, 0.5, 0.1, policy, states, actions)
-	iterEpisodes(10000, agent, dungeon)
+	strategy := explorer.NewBoltzmann[[2]int](schedule.NewExponential(1.0, 0.05, 0.999))
+	rng := rand.New(rand.NewSource(0))
+	agent := newAgent(0.9, learningrate.StepDecay{Initial: 0.5, Factor: 0.5, Every: 100000}, strategy, states, world.Actions(), rng)
+	iterEpisodes(10000, 100, agent, world, states[0], getReward)
 	fmt.Println(agent.q)
+
+	windy := composeWindy()
+	fmt.Println("=========================================")
+	fmt.Println("Windy gridworld:")
+	windy.Print()
+	fmt.Println("G: Goal, -1 per step until reached")
+	fmt.Println("Wind pushes up by", wind)
+	fmt.Println("=========================================")
+	settings := []struct {
+		name         string
+		actionSet    gridworld.ActionSet
+		isStochastic bool
+	}{
+		{"4 moves, steady wind", gridworld.FourWay, false},
+		{"king's moves, steady wind", gridworld.KingMoves, false},
+		{"king's moves and stay, steady wind", gridworld.KingMovesWithStay, false},
+		{"king's moves, stochastic wind", gridworld.KingMoves, true},
+	}
+	windyStart := [2]int{0, 3}
+	for _, setting := range settings {
+		rng := rand.New(rand.NewSource(0))
+		world := gridworld.New(windy, setting.actionSet).WithWind(wind)
+		if setting.isStochastic {
+			world.WithStochasticWind(wind, rng)
+		}
+		strategy := explorer.NewEpsilonGreedy[[2]int](schedule.Constant(0.1))
+		agent := newAgent(1.0, learningrate.Constant(0.5), strategy, getStates(windy), world.Actions(), rng)
+		returns := iterEpisodes(500, 10000, agent, world, windyStart, getStepReward)
+		fmt.Printf("%-36s mean steps over last 100 episodes: %.1f\n", setting.name, -meanOfLast(returns, 100))
+	}
+
+	cliff, cliffBlocks := composeCliff()
+	fmt.Println("=========================================")
+	fmt.Println("Cliff walking:")
+	cliff.Print()
+	fmt.Println("G: Goal, -1 per step until reached")
+	fmt.Println("Blocks between S and G are a cliff: -100 and back to S")
+	fmt.Println("=========================================")
+	cliffWorld := gridworld.New(cliff, gridworld.FourWay).WithCliff(cliffBlocks, -100.0)
+	runs := 50
+	sum := 0.0
+	for run := 0; run < runs; run++ {
+		rng := rand.New(rand.NewSource(int64(run)))
+		strategy := explorer.NewEpsilonGreedy[[2]int](schedule.Constant(0.1))
+		agent := newAgent(1.0, learningrate.Constant(0.5), strategy, getStates(cliff), cliffWorld.Actions(), rng)
+		returns := iterEpisodes(500, 1000, agent, cliffWorld, [2]int{0, 3}, getStepReward)
+		sum += meanOfLast(returns, 100)
+	}
+	fmt.Printf("SARSA mean return over last 100 of 500 episodes, %d runs: %.1f\n", runs, sum/float64(runs))
 }
@@ -6,6 +6,10 @@ import (
 	"math/rand"
 
 	"github.com/marubontan/go-maze/maze"
+	"reinforcement-learning-playground/explorer"
+	"reinforcement-learning-playground/gridworld"
+	"reinforcement-learning-playground/learningrate"
+	"reinforcement-learning-playground/schedule"
 )
 
 func composeDungen() *maze.Maze {
@@ -33,12 +37,23 @@ func printDungenConf() {
 	fmt.Println(("G: Goal with Reward 1"))
 }
 
-const (
-	Left = iota
-	Right
-	Up
-	Down
-)
+func composeCliff() (*maze.Maze, [][2]int) {
+	cliff := maze.NewMaze(4, 12)
+	var err error
+	err = cliff.SetStart(0, 3)
+	if err != nil {
+		panic(err)
+	}
+	err = cliff.SetGoal(11, 3)
+	if err != nil {
+		panic(err)
+	}
+	blocks := make([][2]int, 0)
+	for x := 1; x < 11; x++ {
+		blocks = append(blocks, [2]int{x, 3})
+	}
+	return cliff, blocks
+}
 
 type State [][2]int
 
@@ -54,57 +69,84 @@ func getStates(m *maze.Maze) State {
 
 type Policy map[[2]int]map[int]float64
 
-var actions = []int{Left, Right, Up, Down}
-
 type Agent struct {
-	gamma   float64
-	policy  Policy
-	b       Policy
-	alpha   float64
-	epsilon float64
-	q       map[[2]int]map[int]float64
+	gamma    float64
+	policy   Policy
+	alpha    learningrate.Rate
+	visits   map[[2]int]map[int]int
+	steps    int
+	explorer explorer.Explorer[[2]int]
+	q        map[[2]int]map[int]float64
+	actions  []int
+	rng      *rand.Rand
 }
 
-func newAgent(gamma float64, alpha float64, epsilon float64, policy Policy, b Policy, states [][2]int, actions []int) *Agent {
+func newAgent(gamma float64, alpha learningrate.Rate, explorer explorer.Explorer[[2]int], policy Policy, states [][2]int, actions []int, rng *rand.Rand) *Agent {
 	q := make(map[[2]int]map[int]float64)
+	visits := make(map[[2]int]map[int]int)
 	for _, state := range states {
 		q[state] = make(map[int]float64)
+		visits[state] = make(map[int]int)
 		for _, action := range actions {
-			q[state][action] = 0.0
+			q[state][action] = explorer.InitialQ()
+			visits[state][action] = 0
 		}
 	}
 
 	return &Agent{
-		gamma:   gamma,
-		policy:  policy,
-		b:       b,
-		epsilon: epsilon,
-		alpha:   alpha,
-		q:       q,
+		gamma:    gamma,
+		policy:   policy,
+		explorer: explorer,
+		alpha:    alpha,
+		visits:   visits,
+		q:        q,
+		actions:  actions,
+		rng:      rng,
 	}
 }
 
+// getAction samples the behaviour policy from the explorer's current
+// distribution over q, so a decaying schedule reaches every state, not just
+// the ones updated since.
 func (a *Agent) getAction(state [2]int) (int, error) {
-	statePolicy := a.b[state]
+	statePolicy := a.explorer.Probs(state, a.q[state])
 	cumProb := 0.0
-	for action, prob := range statePolicy {
-		cumProb += prob
-		if rand.Float64() < cumProb {
+	sample := a.rng.Float64()
+	for _, action := range a.actions {
+		cumProb += statePolicy[action]
+		if sample < cumProb {
+			a.explorer.Observe(state, action)
 			return action, nil
 		}
 	}
 	return -1, errors.New("action not found")
 }
 
-func (a *Agent) step(state [2]int, action int, m *maze.Maze) ([2]int, float64, bool) {
-	goalX, goalY, err := m.GetGoal()
+// step pays the world's cliff reward instead of getReward when the move
+// ran into the cliff.
+func (a *Agent) step(state [2]int, action int, t int, maxSteps int, w *gridworld.World, getReward gridworld.Reward) ([2]int, float64, bool, bool) {
+	goalX, goalY, err := w.Maze.GetGoal()
 	if err != nil {
 		panic(err)
 	}
-	nextState := getNextState(state, action, m)
+	nextState, fell := w.Move(state, action)
 	reward := getReward(nextState, goalX, goalY)
+	if fell {
+		reward = w.CliffReward
+	}
 	isGoal := nextState[0] == goalX && nextState[1] == goalY
-	return nextState, reward, isGoal
+	truncated := !isGoal && t >= maxSteps
+	return nextState, reward, isGoal, truncated
+}
+
+func (a *Agent) stepSize(state [2]int, action int) float64 {
+	a.visits[state][action]++
+	a.steps++
+	return a.alpha.Alpha(a.visitCount(state, action), a.steps)
+}
+
+func (a *Agent) visitCount(state [2]int, action int) int {
+	return a.visits[state][action]
 }
 
 func (a *Agent) update(state [2]int, nextState [2]int, action int, reward float64, isGoal bool) {
@@ -112,7 +154,7 @@ func (a *Agent) update(state [2]int, nextState [2]int, action int, reward float6
 	if isGoal {
 		maxQ = 0.0
 	} else {
-		for i, action := range actions {
+		for i, action := range a.actions {
 			if i == 0 {
 				maxQ = a.q[nextState][action]
 			} else {
@@ -123,80 +165,37 @@ func (a *Agent) update(state [2]int, nextState [2]int, action int, reward float6
 		}
 	}
 	target := reward + a.gamma*maxQ
-	a.q[state][action] += (target - a.q[state][action]) * a.alpha
+	a.q[state][action] += (target - a.q[state][action]) * a.stepSize(state, action)
 
 	a.policy[state] = a.greedyProbs(state, 0.0)
-	a.b[state] = a.greedyProbs(state, a.epsilon)
-
 }
 
-func newPolicy(m *maze.Maze) Policy {
-	states := getStates(m)
+func newPolicy(w *gridworld.World) Policy {
+	states := getStates(w.Maze)
 	var policy Policy = make(map[[2]int]map[int]float64)
 	for _, state := range states {
 		statePolicy := make(map[int]float64)
-		for _, action := range actions {
-			statePolicy[action] = 0.25
+		for _, action := range w.Actions() {
+			statePolicy[action] = 1.0 / float64(len(w.Actions()))
 		}
 		policy[state] = statePolicy
 	}
 	return policy
 
 }
-func argmax(data map[int]float64) int {
-	var maxKey int
-	var maxValue float64
-
-	for key, value := range data {
-		maxKey = key
-		maxValue = value
-		break
-	}
-
-	for key, value := range data {
-		if value > maxValue {
-			maxKey = key
-			maxValue = value
-		}
-	}
-
-	return maxKey
-}
 func (a *Agent) greedyProbs(state [2]int, epsilon float64) map[int]float64 {
 	stateQ := a.q[state]
-	maxAction := argmax(stateQ)
-	baseProb := epsilon / float64(len(actions))
+	maxActions := explorer.ArgmaxAll(stateQ)
+	baseProb := epsilon / float64(len(a.actions))
+	greedyProb := (1.0 - epsilon) / float64(len(maxActions))
 	actionProbs := make(map[int]float64)
-	for _, action := range actions {
-		if action == maxAction {
-			actionProbs[action] = 1.0 - epsilon + baseProb
-		} else {
-			actionProbs[action] = baseProb
-		}
+	for _, action := range a.actions {
+		actionProbs[action] = baseProb
 	}
-	return actionProbs
-}
-
-func getNextState(state [2]int, action int, m *maze.Maze) [2]int {
-	var nextStateCandidate [2]int
-	nextStateCandidate[0] = state[0]
-	nextStateCandidate[1] = state[1]
-	switch action {
-	case Left:
-		nextStateCandidate[0]--
-	case Right:
-		nextStateCandidate[0]++
-	case Up:
-		nextStateCandidate[1]--
-	case Down:
-		nextStateCandidate[1]++
-	}
-	if m.IsAvailable(nextStateCandidate[0], nextStateCandidate[1]) {
-		return nextStateCandidate
-
+	for _, action := range maxActions {
+		actionProbs[action] += greedyProb
 	}
-	return state
-
+	return actionProbs
 }
 
 func getReward(state [2]int, goalX, goalY int) float64 {
@@ -209,23 +208,37 @@ func getReward(state [2]int, goalX, goalY int) float64 {
 	return 0
 }
 
-func iterEpisodes(episodes int, agent *Agent, dungeon *maze.Maze) {
-	states := getStates(dungeon)
+// getStepReward charges -1 for every move until the goal is reached.
+func getStepReward(state [2]int, goalX, goalY int) float64 {
+	return -1.0
+}
+
+// iterEpisodes starts every episode at start and cuts it off after
+// maxSteps. The last update of a truncated episode still bootstraps from
+// max q(nextState, .) since only reaching the goal ends the return. It
+// returns the undiscounted return of every episode.
+func iterEpisodes(episodes int, maxSteps int, agent *Agent, world *gridworld.World, start [2]int, getReward gridworld.Reward) []float64 {
+	returns := make([]float64, 0, episodes)
 	for i := 0; i < episodes; i++ {
-		state := states[0]
-		for {
+		state := start
+		g := 0.0
+		for t := 1; ; t++ {
 			action, err := agent.getAction(state)
 			if err != nil {
 				panic(err)
 			}
-			nextState, reward, isGoal := agent.step(state, action, dungeon)
+			nextState, reward, isGoal, truncated := agent.step(state, action, t, maxSteps, world, getReward)
 			agent.update(state, nextState, action, reward, isGoal)
-			if isGoal {
+			g += reward
+			if isGoal || truncated {
 				break
 			}
 			state = nextState
 		}
+		returns = append(returns, g)
+		agent.explorer.Step()
 	}
+	return returns
 }
 
 func main() {
@@ -236,10 +249,33 @@ func main() {
 	fmt.Println("=========================================")
 	printDungenConf()
 	fmt.Println("=========================================")
-	policy := newPolicy(dungeon)
-	b := newPolicy(dungeon)
+	world := gridworld.New(dungeon, gridworld.FourWay)
+	policy := newPolicy(world)
 	states := getStates(dungeon)
-	agent := newAgent(0.9, 0.9, 0.1, policy, b, states, actions)
-	iterEpisodes(10000, agent, dungeon)
+	strategy := explorer.NewUCB[[2]int](1.0)
+	rng := rand.New(rand.NewSource(0))
+	agent := newAgent(0.9, learningrate.Polynomial{Omega: 0.8}, strategy, policy, states, world.Actions(), rng)
+	iterEpisodes(10000, 100, agent, world, states[0], getReward)
 	fmt.Println(agent.q)
+
+	cliff, cliffBlocks := composeCliff()
+	fmt.Println("=========================================")
+	fmt.Println("Cliff walking:")
+	cliff.Print()
+	fmt.Println("G: Goal, -1 per step until reached")
+	fmt.Println("Blocks between S and G are a cliff: -100 and back to S")
+	fmt.Println("=========================================")
+	cliffWorld := gridworld.New(cliff, gridworld.FourWay).WithCliff(cliffBlocks, -100.0)
+	runs := 50
+	sum := 0.0
+	for run := 0; run < runs; run++ {
+		rng := rand.New(rand.NewSource(int64(run)))
+		strategy := explorer.NewEpsilonGreedy[[2]int](schedule.Constant(0.1))
+		agent := newAgent(1.0, learningrate.Constant(0.5), strategy, newPolicy(cliffWorld), getStates(cliff), cliffWorld.Actions(), rng)
+		returns := iterEpisodes(500, 1000, agent, cliffWorld, [2]int{0, 3}, getStepReward)
+		for _, g := range returns[len(returns)-100:] {
+			sum += g
+		}
+	}
+	fmt.Printf("Q-learning mean return over last 100 of 500 episodes, %d runs: %.1f\n", runs, sum/float64(100*runs))
 }
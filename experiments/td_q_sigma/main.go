@@ -0,0 +1,276 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+
+	"github.com/marubontan/go-maze/maze"
+	"reinforcement-learning-playground/explorer"
+	"reinforcement-learning-playground/gridworld"
+)
+
+func composeDungen() *maze.Maze {
+	dungeon := maze.NewMaze(3, 4)
+	var err error
+	err = dungeon.SetStart(0, 2)
+	if err != nil {
+		panic(err)
+	}
+	err = dungeon.SetGoal(3, 0)
+	if err != nil {
+		panic(err)
+	}
+	err = dungeon.SetObstacle(1, 1)
+	if err != nil {
+		panic(err)
+	}
+	return dungeon
+}
+
+func printDungenConf() {
+	fmt.Println("Dungeon Configuration:")
+	fmt.Println("S: Start Position")
+	fmt.Println("X: Obstacle")
+	fmt.Println(("G: Goal with Reward 1"))
+}
+
+type State [][2]int
+
+func getStates(m *maze.Maze) State {
+	blockIndices := make(State, 0)
+	for hI, hBlocks := range m.Blocks {
+		for wI := range hBlocks {
+			blockIndices = append(blockIndices, [2]int{wI, hI})
+		}
+	}
+	return blockIndices
+}
+
+type Policy map[[2]int]map[int]float64
+
+type HistoryElement struct {
+	state      [2]int
+	action     int
+	reward     float64
+	nextState  [2]int
+	nextAction int
+	isGoal     bool
+}
+
+type Agent struct {
+	gamma   float64
+	policy  Policy
+	b       Policy
+	alpha   float64
+	epsilon float64
+	n       int
+	sigma   float64
+	q       map[[2]int]map[int]float64
+	memory  []HistoryElement
+	actions []int
+	rng     *rand.Rand
+}
+
+func newAgent(gamma float64, alpha float64, epsilon float64, n int, sigma float64, policy Policy, b Policy, states [][2]int, actions []int, rng *rand.Rand) *Agent {
+	q := make(map[[2]int]map[int]float64)
+	for _, state := range states {
+		q[state] = make(map[int]float64)
+		for _, action := range actions {
+			q[state][action] = 0.0
+		}
+	}
+
+	return &Agent{
+		gamma:   gamma,
+		policy:  policy,
+		b:       b,
+		epsilon: epsilon,
+		alpha:   alpha,
+		n:       n,
+		sigma:   sigma,
+		q:       q,
+		memory:  make([]HistoryElement, 0, n),
+		actions: actions,
+		rng:     rng,
+	}
+}
+
+func (a *Agent) getAction(state [2]int) (int, error) {
+	statePolicy := a.b[state]
+	cumProb := 0.0
+	sample := a.rng.Float64()
+	for _, action := range a.actions {
+		cumProb += statePolicy[action]
+		if sample < cumProb {
+			return action, nil
+		}
+	}
+	return -1, errors.New("action not found")
+}
+
+func (a *Agent) step(state [2]int, action int, t int, maxSteps int, w *gridworld.World) ([2]int, float64, bool, bool) {
+	goalX, goalY, err := w.Maze.GetGoal()
+	if err != nil {
+		panic(err)
+	}
+	nextState := w.NextState(state, action)
+	reward := getReward(nextState, goalX, goalY)
+	isGoal := nextState[0] == goalX && nextState[1] == goalY
+	truncated := !isGoal && t >= maxSteps
+	return nextState, reward, isGoal, truncated
+}
+
+func (a *Agent) reset() {
+	a.memory = make([]HistoryElement, 0, a.n)
+}
+
+func (a *Agent) expectedQ(state [2]int) float64 {
+	expected := 0.0
+	for action, prob := range a.policy[state] {
+		expected += prob * a.q[state][action]
+	}
+	return expected
+}
+
+// qSigmaReturn computes G_{t:t+n} for the oldest transition in memory.
+// sigma=1 follows the sampled action with the importance sampling ratio and
+// sigma=0 falls back to the tree backup expectation over the target policy.
+// The horizon mixes the same way between Q(S_h, A_h) and the expectation.
+func (a *Agent) qSigmaReturn() float64 {
+	last := a.memory[len(a.memory)-1]
+	var g float64
+	if last.isGoal {
+		g = last.reward
+	} else {
+		sampled := a.q[last.nextState][last.nextAction]
+		g = last.reward + a.gamma*(a.sigma*sampled+(1.0-a.sigma)*a.expectedQ(last.nextState))
+	}
+	for i := len(a.memory) - 2; i >= 0; i-- {
+		memory := a.memory[i]
+		nextAction := a.memory[i+1].action
+		pi := a.policy[memory.nextState][nextAction]
+		rho := pi / a.b[memory.nextState][nextAction]
+		weight := a.sigma*rho + (1.0-a.sigma)*pi
+		g = memory.reward + a.gamma*(weight*(g-a.q[memory.nextState][nextAction])+a.expectedQ(memory.nextState))
+	}
+	return g
+}
+
+func (a *Agent) updateOldest() {
+	memory := a.memory[0]
+	target := a.qSigmaReturn()
+	a.q[memory.state][memory.action] += (target - a.q[memory.state][memory.action]) * a.alpha
+
+	a.policy[memory.state] = a.greedyProbs(memory.state, 0.0)
+	a.b[memory.state] = a.greedyProbs(memory.state, a.epsilon)
+	a.memory = a.memory[1:]
+}
+
+// update flushes the remaining n-step returns at the end of an episode. A
+// truncated episode keeps the expected value of its last nextState in them.
+func (a *Agent) update(state [2]int, nextState [2]int, action int, reward float64, nextAction int, isGoal bool, truncated bool) {
+	a.memory = append(a.memory, HistoryElement{
+		state:      state,
+		action:     action,
+		reward:     reward,
+		nextState:  nextState,
+		nextAction: nextAction,
+		isGoal:     isGoal,
+	})
+	if isGoal || truncated {
+		for len(a.memory) > 0 {
+			a.updateOldest()
+		}
+		return
+	}
+	if len(a.memory) == a.n {
+		a.updateOldest()
+	}
+}
+
+func newPolicy(w *gridworld.World) Policy {
+	states := getStates(w.Maze)
+	var policy Policy = make(map[[2]int]map[int]float64)
+	for _, state := range states {
+		statePolicy := make(map[int]float64)
+		for _, action := range w.Actions() {
+			statePolicy[action] = 1.0 / float64(len(w.Actions()))
+		}
+		policy[state] = statePolicy
+	}
+	return policy
+
+}
+func (a *Agent) greedyProbs(state [2]int, epsilon float64) map[int]float64 {
+	stateQ := a.q[state]
+	maxActions := explorer.ArgmaxAll(stateQ)
+	baseProb := epsilon / float64(len(a.actions))
+	greedyProb := (1.0 - epsilon) / float64(len(maxActions))
+	actionProbs := make(map[int]float64)
+	for _, action := range a.actions {
+		actionProbs[action] = baseProb
+	}
+	for _, action := range maxActions {
+		actionProbs[action] += greedyProb
+	}
+	return actionProbs
+}
+
+func getReward(state [2]int, goalX, goalY int) float64 {
+	if state[0] == goalX && state[1] == goalY {
+		return 1.0
+	}
+	if state[0] == 3 && state[1] == 1 {
+		return -1.0
+	}
+	return 0
+}
+
+// iterEpisodes picks the next action before updating, since the return at
+// the horizon needs A_h whenever sigma is above zero.
+func iterEpisodes(episodes int, maxSteps int, agent *Agent, world *gridworld.World) {
+	states := getStates(world.Maze)
+	for i := 0; i < episodes; i++ {
+		state := states[0]
+		agent.reset()
+		action, err := agent.getAction(state)
+		if err != nil {
+			panic(err)
+		}
+		for t := 1; ; t++ {
+			nextState, reward, isGoal, truncated := agent.step(state, action, t, maxSteps, world)
+			nextAction := -1
+			if !isGoal {
+				nextAction, err = agent.getAction(nextState)
+				if err != nil {
+					panic(err)
+				}
+			}
+			agent.update(state, nextState, action, reward, nextAction, isGoal, truncated)
+			if isGoal || truncated {
+				break
+			}
+			state = nextState
+			action = nextAction
+		}
+	}
+}
+
+func main() {
+	dungeon := composeDungen()
+	fmt.Println("=========================================")
+	fmt.Println("Dungeon:")
+	dungeon.Print()
+	fmt.Println("=========================================")
+	printDungenConf()
+	fmt.Println("=========================================")
+	world := gridworld.New(dungeon, gridworld.FourWay)
+	policy := newPolicy(world)
+	b := newPolicy(world)
+	states := getStates(dungeon)
+	rng := rand.New(rand.NewSource(0))
+	agent := newAgent(0.9, 0.5, 0.1, 3, 0.5, policy, b, states, world.Actions(), rng)
+	iterEpisodes(10000, 100, agent, world)
+	fmt.Println(agent.q)
+}
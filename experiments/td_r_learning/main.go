@@ -0,0 +1,213 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+
+	"github.com/marubontan/go-maze/maze"
+	"reinforcement-learning-playground/explorer"
+	"reinforcement-learning-playground/gridworld"
+)
+
+func composeDungen() *maze.Maze {
+	dungeon := maze.NewMaze(3, 4)
+	var err error
+	err = dungeon.SetStart(0, 2)
+	if err != nil {
+		panic(err)
+	}
+	err = dungeon.SetGoal(3, 0)
+	if err != nil {
+		panic(err)
+	}
+	err = dungeon.SetObstacle(1, 1)
+	if err != nil {
+		panic(err)
+	}
+	return dungeon
+}
+
+func printDungenConf() {
+	fmt.Println("Dungeon Configuration:")
+	fmt.Println("S: Start Position")
+	fmt.Println("X: Obstacle")
+	fmt.Println(("G: Goal with Reward 1, then back to S"))
+}
+
+type State [][2]int
+
+func getStates(m *maze.Maze) State {
+	blockIndices := make(State, 0)
+	for hI, hBlocks := range m.Blocks {
+		for wI := range hBlocks {
+			blockIndices = append(blockIndices, [2]int{wI, hI})
+		}
+	}
+	return blockIndices
+}
+
+type Policy map[[2]int]map[int]float64
+
+type Agent struct {
+	policy    Policy
+	b         Policy
+	alpha     float64
+	beta      float64
+	epsilon   float64
+	avgReward float64
+	q         map[[2]int]map[int]float64
+	actions   []int
+	rng       *rand.Rand
+}
+
+func newAgent(alpha float64, beta float64, epsilon float64, policy Policy, b Policy, states [][2]int, actions []int, rng *rand.Rand) *Agent {
+	q := make(map[[2]int]map[int]float64)
+	for _, state := range states {
+		q[state] = make(map[int]float64)
+		for _, action := range actions {
+			q[state][action] = 0.0
+		}
+	}
+
+	return &Agent{
+		policy:    policy,
+		b:         b,
+		epsilon:   epsilon,
+		alpha:     alpha,
+		beta:      beta,
+		avgReward: 0.0,
+		q:         q,
+		actions:   actions,
+		rng:       rng,
+	}
+}
+
+func (a *Agent) getAction(state [2]int) (int, error) {
+	statePolicy := a.b[state]
+	cumProb := 0.0
+	sample := a.rng.Float64()
+	for _, action := range a.actions {
+		cumProb += statePolicy[action]
+		if sample < cumProb {
+			return action, nil
+		}
+	}
+	return -1, errors.New("action not found")
+}
+
+// step never terminates: reaching the goal pays the reward and teleports the
+// agent back to the start block so the task continues.
+func (a *Agent) step(state [2]int, action int, w *gridworld.World) ([2]int, float64, bool) {
+	goalX, goalY, err := w.Maze.GetGoal()
+	if err != nil {
+		panic(err)
+	}
+	nextState := w.NextState(state, action)
+	reward := getReward(nextState, goalX, goalY)
+	isGoal := nextState[0] == goalX && nextState[1] == goalY
+	if isGoal {
+		startX, startY, err := w.Maze.GetStart()
+		if err != nil {
+			panic(err)
+		}
+		nextState = [2]int{startX, startY}
+	}
+	return nextState, reward, isGoal
+}
+
+func (a *Agent) maxQ(state [2]int) float64 {
+	var maxQ float64
+	for i, action := range a.actions {
+		if i == 0 || maxQ < a.q[state][action] {
+			maxQ = a.q[state][action]
+		}
+	}
+	return maxQ
+}
+
+func (a *Agent) update(state [2]int, nextState [2]int, action int, reward float64) {
+	isGreedy := a.q[state][action] == a.maxQ(state)
+	delta := reward - a.avgReward + a.maxQ(nextState) - a.q[state][action]
+	a.q[state][action] += delta * a.alpha
+	if isGreedy {
+		a.avgReward += a.beta * (reward - a.avgReward + a.maxQ(nextState) - a.maxQ(state))
+	}
+
+	a.policy[state] = a.greedyProbs(state, 0.0)
+	a.b[state] = a.greedyProbs(state, a.epsilon)
+}
+
+func newPolicy(w *gridworld.World) Policy {
+	states := getStates(w.Maze)
+	var policy Policy = make(map[[2]int]map[int]float64)
+	for _, state := range states {
+		statePolicy := make(map[int]float64)
+		for _, action := range w.Actions() {
+			statePolicy[action] = 1.0 / float64(len(w.Actions()))
+		}
+		policy[state] = statePolicy
+	}
+	return policy
+
+}
+func (a *Agent) greedyProbs(state [2]int, epsilon float64) map[int]float64 {
+	stateQ := a.q[state]
+	maxActions := explorer.ArgmaxAll(stateQ)
+	baseProb := epsilon / float64(len(a.actions))
+	greedyProb := (1.0 - epsilon) / float64(len(maxActions))
+	actionProbs := make(map[int]float64)
+	for _, action := range a.actions {
+		actionProbs[action] = baseProb
+	}
+	for _, action := range maxActions {
+		actionProbs[action] += greedyProb
+	}
+	return actionProbs
+}
+
+func getReward(state [2]int, goalX, goalY int) float64 {
+	if state[0] == goalX && state[1] == goalY {
+		return 1.0
+	}
+	if state[0] == 3 && state[1] == 1 {
+		return -1.0
+	}
+	return 0
+}
+
+func iterSteps(steps int, agent *Agent, world *gridworld.World) {
+	startX, startY, err := world.Maze.GetStart()
+	if err != nil {
+		panic(err)
+	}
+	state := [2]int{startX, startY}
+	for i := 0; i < steps; i++ {
+		action, err := agent.getAction(state)
+		if err != nil {
+			panic(err)
+		}
+		nextState, reward, _ := agent.step(state, action, world)
+		agent.update(state, nextState, action, reward)
+		state = nextState
+	}
+}
+
+func main() {
+	dungeon := composeDungen()
+	fmt.Println("=========================================")
+	fmt.Println("Dungeon:")
+	dungeon.Print()
+	fmt.Println("=========================================")
+	printDungenConf()
+	fmt.Println("=========================================")
+	world := gridworld.New(dungeon, gridworld.FourWay)
+	policy := newPolicy(world)
+	b := newPolicy(world)
+	states := getStates(dungeon)
+	rng := rand.New(rand.NewSource(0))
+	agent := newAgent(0.1, 0.01, 0.1, policy, b, states, world.Actions(), rng)
+	iterSteps(100000, agent, world)
+	fmt.Println(agent.q)
+	fmt.Println(agent.avgReward)
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+
+	"github.com/marubontan/go-maze/maze"
+	"reinforcement-learning-playground/approx"
+	"reinforcement-learning-playground/gridworld"
+)
+
+func composeDungen() *maze.Maze {
+	dungeon := maze.NewMaze(3, 4)
+	var err error
+	err = dungeon.SetStart(0, 2)
+	if err != nil {
+		panic(err)
+	}
+	err = dungeon.SetGoal(3, 0)
+	if err != nil {
+		panic(err)
+	}
+	err = dungeon.SetObstacle(1, 1)
+	if err != nil {
+		panic(err)
+	}
+	return dungeon
+}
+
+func printDungenConf() {
+	fmt.Println("Dungeon Configuration:")
+	fmt.Println("S: Start Position")
+	fmt.Println("X: Obstacle")
+	fmt.Println(("G: Goal with Reward 1"))
+}
+
+type State [][2]int
+
+func getStates(m *maze.Maze) State {
+	blockIndices := make(State, 0)
+	for hI, hBlocks := range m.Blocks {
+		for wI := range hBlocks {
+			blockIndices = append(blockIndices, [2]int{wI, hI})
+		}
+	}
+	return blockIndices
+}
+
+type Policy map[[2]int]map[int]float64
+
+type Agent struct {
+	gamma   float64
+	policy  Policy
+	alpha   float64
+	v       *approx.LinearV
+	actions []int
+	rng     *rand.Rand
+}
+
+func newAgent(gamma float64, alpha float64, policy Policy, features approx.Features, actions []int, rng *rand.Rand) *Agent {
+	return &Agent{
+		gamma:   gamma,
+		policy:  policy,
+		alpha:   alpha,
+		v:       approx.NewLinearV(features),
+		actions: actions,
+		rng:     rng,
+	}
+}
+
+func (a *Agent) getAction(state [2]int) (int, error) {
+	statePolicy := a.policy[state]
+	cumProb := 0.0
+	sample := a.rng.Float64()
+	for _, action := range a.actions {
+		cumProb += statePolicy[action]
+		if sample < cumProb {
+			return action, nil
+		}
+	}
+	return -1, errors.New("action not found")
+}
+
+func (a *Agent) step(state [2]int, action int, t int, maxSteps int, w *gridworld.World) ([2]int, float64, bool, bool) {
+	goalX, goalY, err := w.Maze.GetGoal()
+	if err != nil {
+		panic(err)
+	}
+	nextState := w.NextState(state, action)
+	reward := getReward(nextState, goalX, goalY)
+	isGoal := nextState[0] == goalX && nextState[1] == goalY
+	truncated := !isGoal && t >= maxSteps
+	return nextState, reward, isGoal, truncated
+}
+
+func (a *Agent) eval(state [2]int, reward float64, nextState [2]int, isGoal bool) {
+	var nextV float64
+	if isGoal {
+		nextV = 0.0
+	} else {
+		nextV = a.v.Value(nextState)
+	}
+	target := reward + a.gamma*nextV
+	a.v.Update(state, a.alpha, target-a.v.Value(state))
+
+}
+
+func newPolicy(w *gridworld.World) Policy {
+	states := getStates(w.Maze)
+	var policy Policy = make(map[[2]int]map[int]float64)
+	for _, state := range states {
+		statePolicy := make(map[int]float64)
+		for _, action := range w.Actions() {
+			statePolicy[action] = 1.0 / float64(len(w.Actions()))
+		}
+		policy[state] = statePolicy
+	}
+	return policy
+
+}
+
+func getReward(state [2]int, goalX, goalY int) float64 {
+	if state[0] == goalX && state[1] == goalY {
+		return 1.0
+	}
+	if state[0] == 3 && state[1] == 1 {
+		return -1.0
+	}
+	return 0
+}
+
+// iterEpisodes cuts every episode off after maxSteps and still bootstraps
+// the last update of a truncated episode from v(nextState).
+func iterEpisodes(episodes int, maxSteps int, agent *Agent, world *gridworld.World) {
+	states := getStates(world.Maze)
+	for i := 0; i < episodes; i++ {
+		state := states[0]
+		for t := 1; ; t++ {
+			action, err := agent.getAction(state)
+			if err != nil {
+				panic(err)
+			}
+			nextState, reward, isGoal, truncated := agent.step(state, action, t, maxSteps, world)
+			agent.eval(state, reward, nextState, isGoal)
+			if isGoal || truncated {
+				break
+			}
+			state = nextState
+		}
+
+	}
+}
+
+func main() {
+	dungeon := composeDungen()
+	fmt.Println("=========================================")
+	fmt.Println("Dungeon:")
+	dungeon.Print()
+	fmt.Println("=========================================")
+	printDungenConf()
+	fmt.Println("=========================================")
+	world := gridworld.New(dungeon, gridworld.FourWay)
+	policy := newPolicy(world)
+	states := getStates(dungeon)
+	features := approx.NewRadialBasis(dungeon.Width, dungeon.Height, 4, 3, 0.3)
+	rng := rand.New(rand.NewSource(0))
+	agent := newAgent(0.9, 0.05, policy, features, world.Actions(), rng)
+	iterEpisodes(1000, 100, agent, world)
+	v := make(map[[2]int]float64)
+	for _, state := range states {
+		v[state] = agent.v.Value(state)
+	}
+	fmt.Println(v)
+}
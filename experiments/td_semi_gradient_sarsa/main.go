@@ -0,0 +1,170 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+
+	"github.com/marubontan/go-maze/maze"
+	"reinforcement-learning-playground/approx"
+	"reinforcement-learning-playground/explorer"
+	"reinforcement-learning-playground/gridworld"
+	"reinforcement-learning-playground/schedule"
+)
+
+func composeDungen() *maze.Maze {
+	dungeon := maze.NewMaze(3, 4)
+	var err error
+	err = dungeon.SetStart(0, 2)
+	if err != nil {
+		panic(err)
+	}
+	err = dungeon.SetGoal(3, 0)
+	if err != nil {
+		panic(err)
+	}
+	err = dungeon.SetObstacle(1, 1)
+	if err != nil {
+		panic(err)
+	}
+	return dungeon
+}
+
+func printDungenConf() {
+	fmt.Println("Dungeon Configuration:")
+	fmt.Println("S: Start Position")
+	fmt.Println("X: Obstacle")
+	fmt.Println(("G: Goal with Reward 1"))
+}
+
+type State [][2]int
+
+func getStates(m *maze.Maze) State {
+	blockIndices := make(State, 0)
+	for hI, hBlocks := range m.Blocks {
+		for wI := range hBlocks {
+			blockIndices = append(blockIndices, [2]int{wI, hI})
+		}
+	}
+	return blockIndices
+}
+
+type Agent struct {
+	gamma    float64
+	alpha    float64
+	explorer explorer.Explorer[[2]int]
+	q        *approx.LinearQ
+	actions  []int
+	rng      *rand.Rand
+}
+
+func newAgent(gamma float64, alpha float64, explorer explorer.Explorer[[2]int], features approx.Features, actions []int, rng *rand.Rand) *Agent {
+	return &Agent{
+		gamma:    gamma,
+		alpha:    alpha,
+		explorer: explorer,
+		q:        approx.NewLinearQ(features, actions),
+		actions:  actions,
+		rng:      rng,
+	}
+}
+
+func (a *Agent) getAction(state [2]int) (int, error) {
+	statePolicy := a.explorer.Probs(state, a.q.StateValues(state))
+	cumProb := 0.0
+	sample := a.rng.Float64()
+	for _, action := range a.actions {
+		cumProb += statePolicy[action]
+		if sample < cumProb {
+			a.explorer.Observe(state, action)
+			return action, nil
+		}
+	}
+	return -1, errors.New("action not found")
+}
+
+func (a *Agent) step(state [2]int, action int, t int, maxSteps int, w *gridworld.World) ([2]int, float64, bool, bool) {
+	goalX, goalY, err := w.Maze.GetGoal()
+	if err != nil {
+		panic(err)
+	}
+	nextState := w.NextState(state, action)
+	reward := getReward(nextState, goalX, goalY)
+	isGoal := nextState[0] == goalX && nextState[1] == goalY
+	truncated := !isGoal && t >= maxSteps
+	return nextState, reward, isGoal, truncated
+}
+
+func (a *Agent) update(state [2]int, action int, reward float64, nextState [2]int, nextAction int, isGoal bool) {
+	var nextQ float64
+	if isGoal {
+		nextQ = 0.0
+	} else {
+		nextQ = a.q.Value(nextState, nextAction)
+	}
+	target := reward + a.gamma*nextQ
+	a.q.Update(state, action, a.alpha, target-a.q.Value(state, action))
+}
+
+func getReward(state [2]int, goalX, goalY int) float64 {
+	if state[0] == goalX && state[1] == goalY {
+		return 1.0
+	}
+	if state[0] == 3 && state[1] == 1 {
+		return -1.0
+	}
+	return 0
+}
+
+// iterEpisodes cuts every episode off after maxSteps. A truncated episode
+// still samples the next action so that its last update bootstraps from it.
+func iterEpisodes(episodes int, maxSteps int, agent *Agent, world *gridworld.World) {
+	states := getStates(world.Maze)
+	for i := 0; i < episodes; i++ {
+		state := states[0]
+		action, err := agent.getAction(state)
+		if err != nil {
+			panic(err)
+		}
+		for t := 1; ; t++ {
+			nextState, reward, isGoal, truncated := agent.step(state, action, t, maxSteps, world)
+			if isGoal {
+				agent.update(state, action, reward, nextState, -1, isGoal)
+				break
+			}
+			nextAction, err := agent.getAction(nextState)
+			if err != nil {
+				panic(err)
+			}
+			agent.update(state, action, reward, nextState, nextAction, isGoal)
+			if truncated {
+				break
+			}
+			state = nextState
+			action = nextAction
+		}
+		agent.explorer.Step()
+	}
+}
+
+func main() {
+	dungeon := composeDungen()
+	fmt.Println("=========================================")
+	fmt.Println("Dungeon:")
+	dungeon.Print()
+	fmt.Println("=========================================")
+	printDungenConf()
+	fmt.Println("=========================================")
+	world := gridworld.New(dungeon, gridworld.FourWay)
+	states := getStates(dungeon)
+	features := approx.NewOneHot(dungeon.Width, dungeon.Height)
+	strategy := explorer.NewEpsilonGreedy[[2]int](schedule.NewLinear(0.5, 0.05, 500))
+	rng := rand.New(rand.NewSource(0))
+	agent := newAgent(0.9, 0.1, strategy, features, world.Actions(), rng)
+	iterEpisodes(1000, 100, agent, world)
+	q := make(map[[2]int]map[int]float64)
+	for _, state := range states {
+		q[state] = agent.q.StateValues(state)
+	}
+	fmt.Println(q)
+}
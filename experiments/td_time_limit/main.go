@@ -0,0 +1,256 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/marubontan/go-maze/maze"
+	"reinforcement-learning-playground/gridworld"
+)
+
+func composeDungen() *maze.Maze {
+	dungeon := maze.NewMaze(3, 4)
+	var err error
+	err = dungeon.SetStart(0, 2)
+	if err != nil {
+		panic(err)
+	}
+	err = dungeon.SetGoal(3, 0)
+	if err != nil {
+		panic(err)
+	}
+	err = dungeon.SetObstacle(1, 1)
+	if err != nil {
+		panic(err)
+	}
+	return dungeon
+}
+
+func printDungenConf() {
+	fmt.Println("Dungeon Configuration:")
+	fmt.Println("S: Start Position")
+	fmt.Println("X: Obstacle")
+	fmt.Println(("G: Goal with Reward 1"))
+}
+
+type State [][2]int
+
+func getStates(m *maze.Maze) State {
+	blockIndices := make(State, 0)
+	for hI, hBlocks := range m.Blocks {
+		for wI := range hBlocks {
+			blockIndices = append(blockIndices, [2]int{wI, hI})
+		}
+	}
+	return blockIndices
+}
+
+type Policy map[[2]int]map[int]float64
+
+type Agent struct {
+	gamma   float64
+	policy  Policy
+	alpha   float64
+	v       map[[2]int]float64
+	actions []int
+	rng     *rand.Rand
+}
+
+func newAgent(gamma float64, alpha float64, policy Policy, states [][2]int, actions []int, rng *rand.Rand) *Agent {
+	v := make(map[[2]int]float64)
+	for _, state := range states {
+		v[state] = 0.0
+	}
+
+	return &Agent{
+		gamma:   gamma,
+		policy:  policy,
+		alpha:   alpha,
+		v:       v,
+		actions: actions,
+		rng:     rng,
+	}
+}
+
+func (a *Agent) getAction(state [2]int) (int, error) {
+	statePolicy := a.policy[state]
+	cumProb := 0.0
+	sample := a.rng.Float64()
+	for _, action := range a.actions {
+		cumProb += statePolicy[action]
+		if sample < cumProb {
+			return action, nil
+		}
+	}
+	return -1, errors.New("action not found")
+}
+
+func (a *Agent) step(state [2]int, action int, t int, maxSteps int, w *gridworld.World) ([2]int, float64, bool, bool) {
+	goalX, goalY, err := w.Maze.GetGoal()
+	if err != nil {
+		panic(err)
+	}
+	nextState := w.NextState(state, action)
+	reward := getReward(nextState, goalX, goalY)
+	isGoal := nextState[0] == goalX && nextState[1] == goalY
+	truncated := !isGoal && t >= maxSteps
+	return nextState, reward, isGoal, truncated
+}
+
+func (a *Agent) eval(state [2]int, reward float64, nextState [2]int, terminated bool) {
+	var nextV float64
+	if terminated {
+		nextV = 0.0
+	} else {
+		nextV = a.v[nextState]
+	}
+	target := reward + a.gamma*nextV
+	a.v[state] += (target - a.v[state]) * a.alpha
+}
+
+func newPolicy(w *gridworld.World) Policy {
+	states := getStates(w.Maze)
+	var policy Policy = make(map[[2]int]map[int]float64)
+	for _, state := range states {
+		statePolicy := make(map[int]float64)
+		for _, action := range w.Actions() {
+			statePolicy[action] = 1.0 / float64(len(w.Actions()))
+		}
+		policy[state] = statePolicy
+	}
+	return policy
+}
+
+func getReward(state [2]int, goalX, goalY int) float64 {
+	if state[0] == goalX && state[1] == goalY {
+		return 1.0
+	}
+	if state[0] == 3 && state[1] == 1 {
+		return -1.0
+	}
+	return 0
+}
+
+// getStartStates lists every cell an episode can start from so that short,
+// truncated episodes still visit the whole maze.
+func getStartStates(m *maze.Maze) [][2]int {
+	goalX, goalY, err := m.GetGoal()
+	if err != nil {
+		panic(err)
+	}
+	starts := make([][2]int, 0)
+	for _, state := range getStates(m) {
+		if m.IsAvailable(state[0], state[1]) && !(state[0] == goalX && state[1] == goalY) {
+			starts = append(starts, state)
+		}
+	}
+	return starts
+}
+
+// iterEpisodes cuts every episode off after maxSteps. With bootstrap the
+// last transition of a truncated episode keeps v(nextState) in its target;
+// without it the cut is treated as if the episode had terminated there.
+func iterEpisodes(episodes int, maxSteps int, bootstrap bool, agent *Agent, world *gridworld.World) {
+	starts := getStartStates(world.Maze)
+	for i := 0; i < episodes; i++ {
+		state := starts[agent.rng.Intn(len(starts))]
+		for t := 1; ; t++ {
+			action, err := agent.getAction(state)
+			if err != nil {
+				panic(err)
+			}
+			nextState, reward, isGoal, truncated := agent.step(state, action, t, maxSteps, world)
+			agent.eval(state, reward, nextState, isGoal || (truncated && !bootstrap))
+			if isGoal || truncated {
+				break
+			}
+			state = nextState
+		}
+	}
+}
+
+// evaluatePolicy sweeps the Bellman expectation equation to convergence and
+// serves as the reference the sampled estimates are compared against.
+func evaluatePolicy(gamma float64, policy Policy, states [][2]int, world *gridworld.World) map[[2]int]float64 {
+	goalX, goalY, err := world.Maze.GetGoal()
+	if err != nil {
+		panic(err)
+	}
+	v := make(map[[2]int]float64)
+	for {
+		maxDelta := 0.0
+		for _, state := range states {
+			if state[0] == goalX && state[1] == goalY {
+				continue
+			}
+			newV := 0.0
+			for action, prob := range policy[state] {
+				nextState := world.NextState(state, action)
+				reward := getReward(nextState, goalX, goalY)
+				if nextState[0] == goalX && nextState[1] == goalY {
+					newV += prob * reward
+				} else {
+					newV += prob * (reward + gamma*v[nextState])
+				}
+			}
+			maxDelta = math.Max(maxDelta, math.Abs(newV-v[state]))
+			v[state] = newV
+		}
+		if maxDelta < 1e-10 {
+			return v
+		}
+	}
+}
+
+func main() {
+	dungeon := composeDungen()
+	fmt.Println("=========================================")
+	fmt.Println("Dungeon:")
+	dungeon.Print()
+	fmt.Println("=========================================")
+	printDungenConf()
+	fmt.Println("=========================================")
+	world := gridworld.New(dungeon, gridworld.FourWay)
+	policy := newPolicy(world)
+	states := getStartStates(dungeon)
+	reference := evaluatePolicy(0.9, policy, states, world)
+
+	settings := []struct {
+		name      string
+		maxSteps  int
+		bootstrap bool
+	}{
+		{"no limit", 100000, true},
+		{"limit 5, bootstrap", 5, true},
+		{"limit 5, as terminal", 5, false},
+	}
+	fmt.Printf("%-8s %9s", "state", "reference")
+	for _, setting := range settings {
+		fmt.Printf(" %21s", setting.name)
+	}
+	fmt.Println()
+	estimates := make([]map[[2]int]float64, len(settings))
+	for i, setting := range settings {
+		rng := rand.New(rand.NewSource(0))
+		agent := newAgent(0.9, 0.002, policy, getStates(dungeon), world.Actions(), rng)
+		iterEpisodes(100000, setting.maxSteps, setting.bootstrap, agent, world)
+		estimates[i] = agent.v
+	}
+	maxErrors := make([]float64, len(settings))
+	for _, state := range states {
+		fmt.Printf("%-8s %9.3f", fmt.Sprint(state), reference[state])
+		for i, v := range estimates {
+			fmt.Printf(" %21.3f", v[state])
+			maxErrors[i] = math.Max(maxErrors[i], math.Abs(v[state]-reference[state]))
+		}
+		fmt.Println()
+	}
+	fmt.Printf("%-18s", "max abs error")
+	for _, maxError := range maxErrors {
+		fmt.Printf(" %21.3f", maxError)
+	}
+	fmt.Println()
+	fmt.Println("=========================================")
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"reinforcement-learning-playground/gridworld"
+)
+
+// maxAbsError trains TD(0) on episodes cut off after maxSteps and returns
+// the largest deviation from the values of the Bellman expectation sweep.
+func maxAbsError(t *testing.T, maxSteps int, bootstrap bool) float64 {
+	t.Helper()
+	dungeon := composeDungen()
+	world := gridworld.New(dungeon, gridworld.FourWay)
+	policy := newPolicy(world)
+	states := getStartStates(dungeon)
+	reference := evaluatePolicy(0.9, policy, states, world)
+
+	rng := rand.New(rand.NewSource(0))
+	agent := newAgent(0.9, 0.002, policy, getStates(dungeon), world.Actions(), rng)
+	iterEpisodes(100000, maxSteps, bootstrap, agent, world)
+	maxError := 0.0
+	for _, state := range states {
+		maxError = math.Max(maxError, math.Abs(agent.v[state]-reference[state]))
+	}
+	return maxError
+}
+
+func TestBootstrapOnTruncation(t *testing.T) {
+	bootstrapped := maxAbsError(t, 5, true)
+	asTerminal := maxAbsError(t, 5, false)
+	if bootstrapped >= asTerminal {
+		t.Fatalf("max error bootstrapping on truncation %.3f, not below treating it as terminal %.3f", bootstrapped, asTerminal)
+	}
+	if bootstrapped > 0.05 {
+		t.Fatalf("max error bootstrapping on truncation %.3f, want at most 0.05", bootstrapped)
+	}
+}
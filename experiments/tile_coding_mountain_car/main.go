@@ -0,0 +1,133 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+
+	"reinforcement-learning-playground/classiccontrol"
+	"reinforcement-learning-playground/explorer"
+	"reinforcement-learning-playground/schedule"
+	"reinforcement-learning-playground/tilecoding"
+)
+
+type Agent struct {
+	gamma    float64
+	alpha    float64
+	explorer explorer.Explorer[explorer.NoState]
+	actions  []int
+	coder    *tilecoding.TileCoder
+	w        []float64
+	rng      *rand.Rand
+}
+
+func newAgent(gamma float64, alpha float64, explorer explorer.Explorer[explorer.NoState], actions []int, coder *tilecoding.TileCoder, rng *rand.Rand) *Agent {
+	w := make([]float64, coder.Size())
+	for i := range w {
+		w[i] = explorer.InitialQ() / float64(coder.NumTilings())
+	}
+	return &Agent{
+		gamma:    gamma,
+		alpha:    alpha / float64(coder.NumTilings()),
+		explorer: explorer,
+		actions:  actions,
+		coder:    coder,
+		w:        w,
+		rng:      rng,
+	}
+}
+
+func (a *Agent) value(observation []float64, action int) float64 {
+	q := 0.0
+	for _, index := range a.coder.Active(observation, action) {
+		q += a.w[index]
+	}
+	return q
+}
+
+func (a *Agent) stateValues(observation []float64) map[int]float64 {
+	stateQ := make(map[int]float64)
+	for _, action := range a.actions {
+		stateQ[action] = a.value(observation, action)
+	}
+	return stateQ
+}
+
+func (a *Agent) getAction(observation []float64) (int, error) {
+	statePolicy := a.explorer.Probs(explorer.NoState{}, a.stateValues(observation))
+	cumProb := 0.0
+	sample := a.rng.Float64()
+	for _, action := range a.actions {
+		cumProb += statePolicy[action]
+		if sample < cumProb {
+			return action, nil
+		}
+	}
+	return -1, errors.New("action not found")
+}
+
+func (a *Agent) update(observation []float64, action int, reward float64, nextObservation []float64, nextAction int, isGoal bool) {
+	var nextQ float64
+	if isGoal {
+		nextQ = 0.0
+	} else {
+		nextQ = a.value(nextObservation, nextAction)
+	}
+	delta := reward + a.gamma*nextQ - a.value(observation, action)
+	for _, index := range a.coder.Active(observation, action) {
+		a.w[index] += a.alpha * delta
+	}
+}
+
+func iterEpisodes(episodes int, agent *Agent, env *classiccontrol.MountainCar) []int {
+	lengths := make([]int, 0, episodes)
+	for i := 0; i < episodes; i++ {
+		observation := env.Reset()
+		action, err := agent.getAction(observation)
+		if err != nil {
+			panic(err)
+		}
+		length := 0
+		for {
+			nextObservation, reward, isGoal, truncated := env.Step(action)
+			length++
+			if isGoal {
+				agent.update(observation, action, reward, nextObservation, -1, isGoal)
+				break
+			}
+			nextAction, err := agent.getAction(nextObservation)
+			if err != nil {
+				panic(err)
+			}
+			agent.update(observation, action, reward, nextObservation, nextAction, isGoal)
+			if truncated {
+				break
+			}
+			observation = nextObservation
+			action = nextAction
+		}
+		agent.explorer.Step()
+		lengths = append(lengths, length)
+	}
+	return lengths
+}
+
+func main() {
+	rng := rand.New(rand.NewSource(0))
+	env := classiccontrol.NewMountainCar(rng)
+	env.MaxSteps = 0
+	coder := tilecoding.NewTileCoder(4096, 8, 8, env.Low(), env.High())
+	strategy := explorer.NewEpsilonGreedy[explorer.NoState](schedule.Constant(0.0))
+	agent := newAgent(1.0, 0.5, strategy, env.Actions(), coder, rng)
+	lengths := iterEpisodes(500, agent, env)
+	fmt.Println("=========================================")
+	fmt.Println("Mountain Car steps per episode (mean of 50):")
+	for i := 0; i < len(lengths); i += 50 {
+		sum := 0
+		for _, length := range lengths[i : i+50] {
+			sum += length
+		}
+		fmt.Printf("episodes %3d-%3d: %.1f\n", i+1, i+50, float64(sum)/50.0)
+	}
+	fmt.Println("=========================================")
+}
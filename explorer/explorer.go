@@ -0,0 +1,205 @@
+package explorer
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+
+	"reinforcement-learning-playground/schedule"
+)
+
+// Explorer turns the action values of a state into the behaviour
+// distribution an agent samples from. S is whatever identifies a state to
+// the agent; agents without tabulated states use NoState.
+type Explorer[S comparable] interface {
+	Probs(state S, stateQ map[int]float64) map[int]float64
+	Observe(state S, action int)
+	Step()
+	InitialQ() float64
+}
+
+// NoState keys the explorers of agents whose states are not enumerable, such
+// as bandits or agents with function approximation. Explorers that count
+// visits per state, like UCB, then see every step as the same state.
+type NoState struct{}
+
+func ArgmaxAll(data map[int]float64) []int {
+	maxKeys := make([]int, 0)
+	var maxValue float64
+
+	for key, value := range data {
+		if len(maxKeys) == 0 || value > maxValue {
+			maxKeys = []int{key}
+			maxValue = value
+		} else if value == maxValue {
+			maxKeys = append(maxKeys, key)
+		}
+	}
+	sort.Ints(maxKeys)
+
+	return maxKeys
+}
+
+// Argmax breaks ties between maximizing keys uniformly at random.
+func Argmax(data map[int]float64, rng *rand.Rand) int {
+	maxKeys := ArgmaxAll(data)
+	return maxKeys[rng.Intn(len(maxKeys))]
+}
+
+type EpsilonGreedy[S comparable] struct {
+	epsilon schedule.Schedule
+	t       int
+}
+
+func NewEpsilonGreedy[S comparable](epsilon schedule.Schedule) *EpsilonGreedy[S] {
+	return &EpsilonGreedy[S]{epsilon: epsilon}
+}
+
+func (e *EpsilonGreedy[S]) Epsilon() float64 {
+	return e.epsilon.Value(e.t)
+}
+
+func (e *EpsilonGreedy[S]) Probs(state S, stateQ map[int]float64) map[int]float64 {
+	epsilon := e.Epsilon()
+	maxActions := ArgmaxAll(stateQ)
+	baseProb := epsilon / float64(len(stateQ))
+	greedyProb := (1.0 - epsilon) / float64(len(maxActions))
+	actionProbs := make(map[int]float64)
+	for action := range stateQ {
+		actionProbs[action] = baseProb
+	}
+	for _, action := range maxActions {
+		actionProbs[action] += greedyProb
+	}
+	return actionProbs
+}
+
+func (e *EpsilonGreedy[S]) Observe(state S, action int) {}
+
+func (e *EpsilonGreedy[S]) Step() {
+	e.t++
+}
+
+func (e *EpsilonGreedy[S]) InitialQ() float64 {
+	return 0.0
+}
+
+type Boltzmann[S comparable] struct {
+	temperature schedule.Schedule
+	t           int
+}
+
+func NewBoltzmann[S comparable](temperature schedule.Schedule) *Boltzmann[S] {
+	return &Boltzmann[S]{temperature: temperature}
+}
+
+// minTemperature keeps a schedule that decays to zero from dividing by
+// zero; at this temperature the distribution is greedy with ties shared.
+const minTemperature = 1e-8
+
+func (b *Boltzmann[S]) Temperature() float64 {
+	return math.Max(b.temperature.Value(b.t), minTemperature)
+}
+
+func (b *Boltzmann[S]) Probs(state S, stateQ map[int]float64) map[int]float64 {
+	temperature := b.Temperature()
+	maxValue := stateQ[ArgmaxAll(stateQ)[0]]
+	sum := 0.0
+	actionProbs := make(map[int]float64)
+	for action, value := range stateQ {
+		actionProbs[action] = math.Exp((value - maxValue) / temperature)
+		sum += actionProbs[action]
+	}
+	for action := range actionProbs {
+		actionProbs[action] /= sum
+	}
+	return actionProbs
+}
+
+func (b *Boltzmann[S]) Observe(state S, action int) {}
+
+func (b *Boltzmann[S]) Step() {
+	b.t++
+}
+
+func (b *Boltzmann[S]) InitialQ() float64 {
+	return 0.0
+}
+
+type UCB[S comparable] struct {
+	c           float64
+	stateCounts map[S]int
+	counts      map[S]map[int]int
+}
+
+func NewUCB[S comparable](c float64) *UCB[S] {
+	return &UCB[S]{
+		c:           c,
+		stateCounts: make(map[S]int),
+		counts:      make(map[S]map[int]int),
+	}
+}
+
+// Probs spreads all mass over the actions with the highest upper confidence bound.
+// Actions never tried in the state are preferred, uniformly among themselves.
+func (u *UCB[S]) Probs(state S, stateQ map[int]float64) map[int]float64 {
+	untried := make([]int, 0)
+	for action := range stateQ {
+		if u.counts[state][action] == 0 {
+			untried = append(untried, action)
+		}
+	}
+	actionProbs := make(map[int]float64)
+	if len(untried) > 0 {
+		for action := range stateQ {
+			actionProbs[action] = 0.0
+		}
+		for _, action := range untried {
+			actionProbs[action] = 1.0 / float64(len(untried))
+		}
+		return actionProbs
+	}
+
+	bounds := make(map[int]float64)
+	logN := math.Log(float64(u.stateCounts[state]))
+	for action, value := range stateQ {
+		bounds[action] = value + u.c*math.Sqrt(logN/float64(u.counts[state][action]))
+	}
+	maxActions := ArgmaxAll(bounds)
+	for action := range stateQ {
+		actionProbs[action] = 0.0
+	}
+	for _, action := range maxActions {
+		actionProbs[action] = 1.0 / float64(len(maxActions))
+	}
+	return actionProbs
+}
+
+func (u *UCB[S]) Observe(state S, action int) {
+	if _, ok := u.counts[state]; !ok {
+		u.counts[state] = make(map[int]int)
+	}
+	u.stateCounts[state]++
+	u.counts[state][action]++
+}
+
+func (u *UCB[S]) Step() {}
+
+func (u *UCB[S]) InitialQ() float64 {
+	return 0.0
+}
+
+// Optimistic starts every action value at initial so that untried actions
+// look attractive to the wrapped explorer.
+type Optimistic[S comparable] struct {
+	Explorer[S]
+	initial float64
+}
+
+func NewOptimistic[S comparable](explorer Explorer[S], initial float64) *Optimistic[S] {
+	return &Optimistic[S]{Explorer: explorer, initial: initial}
+}
+
+func (o *Optimistic[S]) InitialQ() float64 {
+	return o.initial
+}
@@ -0,0 +1,73 @@
+package gridworld
+
+import "reinforcement-learning-playground/approx"
+
+// Reward gives the reward for entering state of a maze whose goal is at
+// (goalX, goalY), in the shape of the experiments' getReward.
+type Reward func(state [2]int, goalX, goalY int) float64
+
+// Env exposes a World through the classiccontrol.Env contract with one-hot
+// observations. Every episode starts at Start; entering the goal terminates
+// it and MaxSteps truncates it.
+type Env struct {
+	world    *World
+	reward   Reward
+	features *approx.OneHot
+	state    [2]int
+	Start    [2]int
+	MaxSteps int
+	steps    int
+}
+
+func NewEnv(world *World, reward Reward, start [2]int, maxSteps int) *Env {
+	return &Env{
+		world:    world,
+		reward:   reward,
+		features: approx.NewOneHot(world.Maze.Width, world.Maze.Height),
+		Start:    start,
+		MaxSteps: maxSteps,
+	}
+}
+
+// Observation is the one-hot vector the agents see for state.
+func (e *Env) Observation(state [2]int) []float64 {
+	return e.features.Features(state)
+}
+
+func (e *Env) Dim() int {
+	return e.features.Dim()
+}
+
+func (e *Env) Reset() []float64 {
+	e.state = e.Start
+	e.steps = 0
+	return e.features.Features(e.state)
+}
+
+func (e *Env) Step(action int) ([]float64, float64, bool, bool) {
+	goalX, goalY, err := e.world.Maze.GetGoal()
+	if err != nil {
+		panic(err)
+	}
+	e.state = e.world.NextState(e.state, action)
+	e.steps++
+	reward := e.reward(e.state, goalX, goalY)
+	isGoal := e.state[0] == goalX && e.state[1] == goalY
+	return e.features.Features(e.state), reward, isGoal, !isGoal && e.MaxSteps > 0 && e.steps >= e.MaxSteps
+}
+
+func (e *Env) Actions() []int {
+	return e.world.Actions()
+}
+
+func (e *Env) Low() []float64 {
+	return make([]float64, e.features.Dim())
+}
+
+func (e *Env) High() []float64 {
+	high := make([]float64, e.features.Dim())
+	for i := range high {
+		high[i] = 1.0
+	}
+	return high
+}
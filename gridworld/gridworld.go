@@ -0,0 +1,164 @@
+package gridworld
+
+import (
+	"math/rand"
+
+	"github.com/marubontan/go-maze/maze"
+)
+
+// ActionSet names the actions of an environment and the block offset each
+// one moves by. Action i is the i-th entry.
+type ActionSet struct {
+	Names []string
+	Moves [][2]int
+}
+
+var FourWay = ActionSet{
+	Names: []string{"Left", "Right", "Up", "Down"},
+	Moves: [][2]int{{-1, 0}, {1, 0}, {0, -1}, {0, 1}},
+}
+
+var KingMoves = ActionSet{
+	Names: []string{"Left", "Right", "Up", "Down", "UpLeft", "UpRight", "DownLeft", "DownRight"},
+	Moves: [][2]int{{-1, 0}, {1, 0}, {0, -1}, {0, 1}, {-1, -1}, {1, -1}, {-1, 1}, {1, 1}},
+}
+
+var KingMovesWithStay = ActionSet{
+	Names: append(append([]string{}, KingMoves.Names...), "Stay"),
+	Moves: append(append([][2]int{}, KingMoves.Moves...), [2]int{0, 0}),
+}
+
+func (s ActionSet) Count() int {
+	return len(s.Moves)
+}
+
+func (s ActionSet) Actions() []int {
+	actions := make([]int, s.Count())
+	for i := range actions {
+		actions[i] = i
+	}
+	return actions
+}
+
+// World moves an agent through a maze. Wind and a cliff are optional and
+// are switched on with WithWind, WithStochasticWind and WithCliff.
+type World struct {
+	Maze        *maze.Maze
+	ActionSet   ActionSet
+	Wind        []int
+	CliffReward float64
+	windRng     *rand.Rand
+	cliff       map[[2]int]bool
+}
+
+func New(m *maze.Maze, actionSet ActionSet) *World {
+	return &World{Maze: m, ActionSet: actionSet}
+}
+
+// WithWind pushes the agent up by wind[x] blocks after every move out of
+// column x, stopping early at the edge of the maze or an obstacle.
+func (w *World) WithWind(wind []int) *World {
+	w.Wind = wind
+	return w
+}
+
+// WithStochasticWind is WithWind where the push in every windy column is one
+// more or one less than wind[x] a third of the time each.
+func (w *World) WithStochasticWind(wind []int, rng *rand.Rand) *World {
+	w.Wind = wind
+	w.windRng = rng
+	return w
+}
+
+// WithCliff turns blocks into a cliff. Running into it sends the agent back
+// to the start of the maze, and the move pays reward instead of the usual
+// one.
+func (w *World) WithCliff(blocks [][2]int, reward float64) *World {
+	w.cliff = make(map[[2]int]bool)
+	for _, block := range blocks {
+		w.cliff[block] = true
+	}
+	w.CliffReward = reward
+	return w
+}
+
+func (w *World) IsCliff(state [2]int) bool {
+	return w.cliff[state]
+}
+
+func (w *World) Actions() []int {
+	return w.ActionSet.Actions()
+}
+
+func (w *World) ActionName(action int) string {
+	return w.ActionSet.Names[action]
+}
+
+func (w *World) candidate(state [2]int, action int) [2]int {
+	move := w.ActionSet.Moves[action]
+	return [2]int{state[0] + move[0], state[1] + move[1]}
+}
+
+// NextState moves by the action's offset, or stays when the target block is
+// outside the maze or an obstacle, and then applies the wind and the cliff.
+func (w *World) NextState(state [2]int, action int) [2]int {
+	nextState, _ := w.Move(state, action)
+	return nextState
+}
+
+// Move is NextState that also reports whether the agent ran into the cliff,
+// in which case the returned state is the start of the maze.
+func (w *World) Move(state [2]int, action int) ([2]int, bool) {
+	nextState := w.candidate(state, action)
+	if !w.Maze.IsAvailable(nextState[0], nextState[1]) {
+		nextState = state
+	}
+	push := w.push(state[0])
+	for i := 0; i < push; i++ {
+		if !w.Maze.IsAvailable(nextState[0], nextState[1]-1) {
+			break
+		}
+		nextState[1]--
+	}
+	if w.cliff[nextState] {
+		startX, startY, err := w.Maze.GetStart()
+		if err != nil {
+			panic(err)
+		}
+		return [2]int{startX, startY}, true
+	}
+	return nextState, false
+}
+
+// push is the wind in column x, which only varies when the column is windy.
+func (w *World) push(x int) int {
+	if w.Wind == nil {
+		return 0
+	}
+	push := w.Wind[x]
+	if w.windRng != nil && push > 0 {
+		push += w.windRng.Intn(3) - 1
+	}
+	return push
+}
+
+// ActionMask reports for every action whether it leads to an available
+// block from state.
+func (w *World) ActionMask(state [2]int) []bool {
+	mask := make([]bool, w.ActionSet.Count())
+	for _, action := range w.Actions() {
+		nextStateCandidate := w.candidate(state, action)
+		mask[action] = w.Maze.IsAvailable(nextStateCandidate[0], nextStateCandidate[1])
+	}
+	return mask
+}
+
+func (w *World) LegalActions(state [2]int) []int {
+	legal := make([]int, 0, w.ActionSet.Count())
+	for action, isLegal := range w.ActionMask(state) {
+		if isLegal {
+			legal = append(legal, action)
+		}
+	}
+	return legal
+}
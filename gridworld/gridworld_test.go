@@ -0,0 +1,55 @@
+package gridworld
+
+import (
+	"testing"
+
+	"github.com/marubontan/go-maze/maze"
+)
+
+func newMaze(t *testing.T, height, width int, start, goal [2]int) *maze.Maze {
+	t.Helper()
+	m := maze.NewMaze(height, width)
+	if err := m.SetStart(start[0], start[1]); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.SetGoal(goal[0], goal[1]); err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestWindPushesUpFromTheColumnLeft(t *testing.T) {
+	wind := []int{0, 0, 0, 1, 1, 1, 2, 2, 1, 0}
+	w := New(newMaze(t, 7, 10, [2]int{0, 3}, [2]int{7, 3}), FourWay).WithWind(wind)
+	right := 1
+	cases := []struct {
+		state [2]int
+		want  [2]int
+	}{
+		{[2]int{0, 3}, [2]int{1, 3}},
+		{[2]int{3, 3}, [2]int{4, 2}},
+		{[2]int{6, 3}, [2]int{7, 1}},
+		{[2]int{6, 1}, [2]int{7, 0}},
+		{[2]int{6, 0}, [2]int{7, 0}},
+	}
+	for _, c := range cases {
+		if got := w.NextState(c.state, right); got != c.want {
+			t.Errorf("NextState(%v, Right) = %v, want %v", c.state, got, c.want)
+		}
+	}
+}
+
+func TestCliffSendsBackToStart(t *testing.T) {
+	cliff := [][2]int{{1, 3}, {2, 3}}
+	w := New(newMaze(t, 4, 4, [2]int{0, 3}, [2]int{3, 3}), FourWay).WithCliff(cliff, -100.0)
+	right, up := 1, 2
+	if got, fell := w.Move([2]int{0, 3}, right); !fell || got != [2]int{0, 3} {
+		t.Errorf("Move into the cliff = %v, %v, want [0 3], true", got, fell)
+	}
+	if got, fell := w.Move([2]int{0, 3}, up); fell || got != [2]int{0, 2} {
+		t.Errorf("Move away from the cliff = %v, %v, want [0 2], false", got, fell)
+	}
+	if w.CliffReward != -100.0 {
+		t.Errorf("CliffReward = %v, want -100", w.CliffReward)
+	}
+}
@@ -0,0 +1,48 @@
+package learningrate
+
+import "math"
+
+// Rate gives the step size for an update given how many times the updated
+// state-action pair has been visited and how many updates the agent has made.
+type Rate interface {
+	Alpha(visits int, step int) float64
+}
+
+type Constant float64
+
+func (c Constant) Alpha(visits int, step int) float64 {
+	return float64(c)
+}
+
+// Harmonic is 1/N, the sample average.
+type Harmonic struct{}
+
+func (h Harmonic) Alpha(visits int, step int) float64 {
+	return 1.0 / float64(visits)
+}
+
+// Polynomial is 1/N^omega. Omega in (0.5, 1] satisfies the Robbins-Monro
+// conditions.
+type Polynomial struct {
+	Omega float64
+}
+
+func (p Polynomial) Alpha(visits int, step int) float64 {
+	return 1.0 / math.Pow(float64(visits), p.Omega)
+}
+
+// StepDecay multiplies the initial rate by factor every `every` updates,
+// regardless of which state-action pair is updated. An Every of zero never
+// decays.
+type StepDecay struct {
+	Initial float64
+	Factor  float64
+	Every   int
+}
+
+func (s StepDecay) Alpha(visits int, step int) float64 {
+	if s.Every <= 0 {
+		return s.Initial
+	}
+	return s.Initial * math.Pow(s.Factor, float64(step/s.Every))
+}
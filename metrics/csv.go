@@ -0,0 +1,83 @@
+package metrics
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"strconv"
+)
+
+// CSV writes episodes.csv and snapshots.csv into a directory. A snapshot
+// becomes one row per table entry.
+type CSV struct {
+	files     []io.Closer
+	episodes  *csv.Writer
+	snapshots *csv.Writer
+}
+
+func NewCSV(dir string) (*CSV, error) {
+	files, err := createFiles(dir, "episodes.csv", "snapshots.csv")
+	if err != nil {
+		return nil, err
+	}
+	c := NewCSVWriter(files[0], files[1])
+	c.files = []io.Closer{files[0], files[1]}
+	return c, nil
+}
+
+// NewCSVWriter writes the episode and snapshot rows to the given writers
+// instead of files. Rows are buffered and flushed by Close.
+func NewCSVWriter(episodes io.Writer, snapshots io.Writer) *CSV {
+	c := &CSV{
+		episodes:  csv.NewWriter(episodes),
+		snapshots: csv.NewWriter(snapshots),
+	}
+	// The headers only reach the buffers here; a failing writer is
+	// reported by Close.
+	c.episodes.Write([]string{"episode", "return", "length", "epsilon", "mean_td_error", "wall_time"})
+	c.snapshots.Write([]string{"episode", "x", "y", "action", "value"})
+	return c
+}
+
+func formatFloat(x float64) string {
+	return strconv.FormatFloat(x, 'g', -1, 64)
+}
+
+func (c *CSV) WriteEpisode(e Episode) error {
+	return c.episodes.Write([]string{
+		strconv.Itoa(e.Episode),
+		formatFloat(e.Return),
+		strconv.Itoa(e.Length),
+		formatFloat(e.Epsilon),
+		formatFloat(e.MeanTDError),
+		formatFloat(e.WallTime),
+	})
+}
+
+func (c *CSV) WriteSnapshot(s Snapshot) error {
+	for _, v := range s.Values {
+		err := c.snapshots.Write([]string{
+			strconv.Itoa(s.Episode),
+			strconv.Itoa(v.State[0]),
+			strconv.Itoa(v.State[1]),
+			v.Action,
+			formatFloat(v.Value),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *CSV) Close() error {
+	errs := make([]error, 0)
+	for _, w := range []*csv.Writer{c.episodes, c.snapshots} {
+		w.Flush()
+		errs = append(errs, w.Error())
+	}
+	for _, f := range c.files {
+		errs = append(errs, f.Close())
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+// JSONL writes episodes.jsonl and snapshots.jsonl into a directory, one
+// JSON object per line.
+type JSONL struct {
+	files     []io.Closer
+	buffers   []*bufio.Writer
+	episodes  *json.Encoder
+	snapshots *json.Encoder
+}
+
+func NewJSONL(dir string) (*JSONL, error) {
+	files, err := createFiles(dir, "episodes.jsonl", "snapshots.jsonl")
+	if err != nil {
+		return nil, err
+	}
+	j := NewJSONLWriter(files[0], files[1])
+	j.files = []io.Closer{files[0], files[1]}
+	return j, nil
+}
+
+// NewJSONLWriter writes the episode and snapshot lines to the given writers
+// instead of files. Lines are buffered and flushed by Close.
+func NewJSONLWriter(episodes io.Writer, snapshots io.Writer) *JSONL {
+	e := bufio.NewWriter(episodes)
+	s := bufio.NewWriter(snapshots)
+	return &JSONL{
+		buffers:   []*bufio.Writer{e, s},
+		episodes:  json.NewEncoder(e),
+		snapshots: json.NewEncoder(s),
+	}
+}
+
+func (j *JSONL) WriteEpisode(e Episode) error {
+	return j.episodes.Encode(e)
+}
+
+func (j *JSONL) WriteSnapshot(s Snapshot) error {
+	return j.snapshots.Encode(s)
+}
+
+func (j *JSONL) Close() error {
+	errs := make([]error, 0)
+	for _, b := range j.buffers {
+		errs = append(errs, b.Flush())
+	}
+	for _, f := range j.files {
+		errs = append(errs, f.Close())
+	}
+	return errors.Join(errs...)
+}
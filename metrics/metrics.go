@@ -0,0 +1,102 @@
+package metrics
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Episode is the per-episode record. Return is the undiscounted sum of
+// rewards, MeanTDError the mean absolute error of the updates made during
+// the episode and WallTime the seconds since the logger was created.
+type Episode struct {
+	Episode     int     `json:"episode"`
+	Return      float64 `json:"return"`
+	Length      int     `json:"length"`
+	Epsilon     float64 `json:"epsilon"`
+	MeanTDError float64 `json:"meanTDError"`
+	WallTime    float64 `json:"wallTime"`
+}
+
+// Value is one entry of a value table. Action is empty for state values.
+type Value struct {
+	State  [2]int  `json:"state"`
+	Action string  `json:"action,omitempty"`
+	Value  float64 `json:"value"`
+}
+
+// Snapshot is a value table as it stood after an episode.
+type Snapshot struct {
+	Episode int     `json:"episode"`
+	Values  []Value `json:"values"`
+}
+
+type Sink interface {
+	WriteEpisode(e Episode) error
+	WriteSnapshot(s Snapshot) error
+	Close() error
+}
+
+// Logger fans records out to its sinks and decides when a value table
+// snapshot is due.
+type Logger struct {
+	sinks         []Sink
+	snapshotEvery int
+	start         time.Time
+}
+
+// NewLogger snapshots every snapshotEvery episodes, never when it is zero.
+func NewLogger(snapshotEvery int, sinks ...Sink) *Logger {
+	return &Logger{sinks: sinks, snapshotEvery: snapshotEvery, start: time.Now()}
+}
+
+func (l *Logger) Episode(e Episode) error {
+	e.WallTime = time.Since(l.start).Seconds()
+	for _, sink := range l.sinks {
+		if err := sink.WriteEpisode(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SnapshotDue reports whether the table after the 1-based episode should be
+// recorded.
+func (l *Logger) SnapshotDue(episode int) bool {
+	return l.snapshotEvery > 0 && episode%l.snapshotEvery == 0
+}
+
+func (l *Logger) Snapshot(episode int, values []Value) error {
+	for _, sink := range l.sinks {
+		if err := sink.WriteSnapshot(Snapshot{episode, values}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (l *Logger) Close() error {
+	errs := make([]error, 0)
+	for _, sink := range l.sinks {
+		errs = append(errs, sink.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// createFiles creates the named files in dir, closing the ones already
+// created if one fails.
+func createFiles(dir string, names ...string) ([]*os.File, error) {
+	files := make([]*os.File, 0, len(names))
+	for _, name := range names {
+		f, err := os.Create(filepath.Join(dir, name))
+		if err != nil {
+			for _, f := range files {
+				f.Close()
+			}
+			return nil, err
+		}
+		files = append(files, f)
+	}
+	return files, nil
+}
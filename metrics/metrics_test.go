@@ -0,0 +1,110 @@
+package metrics
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+var (
+	episodes = []Episode{
+		{Episode: 1, Return: -3.5, Length: 12, Epsilon: 0.1, MeanTDError: 0.25, WallTime: 0.5},
+		{Episode: 2, Return: 1, Length: 4, Epsilon: 0.05, MeanTDError: 0.125, WallTime: 1.5},
+	}
+	snapshot = Snapshot{Episode: 2, Values: []Value{
+		{State: [2]int{0, 2}, Action: "Left", Value: 0.5},
+		{State: [2]int{3, 0}, Action: "Up", Value: -1},
+	}}
+)
+
+func write(t *testing.T, sink Sink) {
+	t.Helper()
+	for _, e := range episodes {
+		if err := sink.WriteEpisode(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := sink.WriteSnapshot(snapshot); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCSVRoundTrip(t *testing.T) {
+	var episodeBuf, snapshotBuf bytes.Buffer
+	sink := NewCSVWriter(&episodeBuf, &snapshotBuf)
+	write(t, sink)
+	if episodeBuf.Len() != 0 || snapshotBuf.Len() != 0 {
+		t.Fatal("rows were written before Close")
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := csv.NewReader(&episodeBuf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		{"episode", "return", "length", "epsilon", "mean_td_error", "wall_time"},
+		{"1", "-3.5", "12", "0.1", "0.25", "0.5"},
+		{"2", "1", "4", "0.05", "0.125", "1.5"},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("episodes.csv = %v, want %v", rows, want)
+	}
+
+	rows, err = csv.NewReader(&snapshotBuf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = [][]string{
+		{"episode", "x", "y", "action", "value"},
+		{"2", "0", "2", "Left", "0.5"},
+		{"2", "3", "0", "Up", "-1"},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("snapshots.csv = %v, want %v", rows, want)
+	}
+}
+
+func TestJSONLRoundTrip(t *testing.T) {
+	var episodeBuf, snapshotBuf bytes.Buffer
+	sink := NewJSONLWriter(&episodeBuf, &snapshotBuf)
+	write(t, sink)
+	if episodeBuf.Len() != 0 || snapshotBuf.Len() != 0 {
+		t.Fatal("lines were written before Close")
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if lines := bytes.Count(episodeBuf.Bytes(), []byte("\n")); lines != len(episodes) {
+		t.Fatalf("episodes.jsonl has %d lines, want %d", lines, len(episodes))
+	}
+	decoder := json.NewDecoder(&episodeBuf)
+	decoder.DisallowUnknownFields()
+	for i, want := range episodes {
+		var got Episode
+		if err := decoder.Decode(&got); err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("episode line %d = %+v, want %+v", i, got, want)
+		}
+	}
+
+	var got Snapshot
+	decoder = json.NewDecoder(&snapshotBuf)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, snapshot) {
+		t.Errorf("snapshot line = %+v, want %+v", got, snapshot)
+	}
+	if decoder.More() {
+		t.Error("snapshots.jsonl has more than one line")
+	}
+}
@@ -0,0 +1,35 @@
+package nn
+
+import "math"
+
+// GradCheck compares the gradients Backward computes for params against
+// central differences of loss and returns the largest relative error.
+// Gradients smaller than 1e-4 are compared against that floor instead so
+// that rounding noise on near zero entries does not dominate.
+// loss must rebuild the graph from params on every call.
+func GradCheck(loss func() *Tensor, params []*Tensor, eps float64) float64 {
+	zeroGrad(params)
+	loss().Backward()
+	analytic := make([][]float64, len(params))
+	for i, param := range params {
+		analytic[i] = append([]float64{}, param.Grad...)
+	}
+
+	maxError := 0.0
+	for i, param := range params {
+		for j := range param.Data {
+			original := param.Data[j]
+			param.Data[j] = original + eps
+			plus := loss().Item()
+			param.Data[j] = original - eps
+			minus := loss().Item()
+			param.Data[j] = original
+			numeric := (plus - minus) / (2 * eps)
+			scale := math.Max(math.Abs(numeric)+math.Abs(analytic[i][j]), 1e-4)
+			if err := math.Abs(numeric-analytic[i][j]) / scale; err > maxError {
+				maxError = err
+			}
+		}
+	}
+	return maxError
+}
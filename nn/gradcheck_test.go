@@ -0,0 +1,81 @@
+package nn
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+const gradCheckTolerance = 1e-6
+
+func randomTensor(rows, cols int, rng *rand.Rand) *Tensor {
+	t := Zeros(rows, cols)
+	for i := range t.Data {
+		t.Data[i] = rng.NormFloat64()
+	}
+	return t
+}
+
+// awayFromZero moves entries near the kink of ReLU out of reach of the
+// finite difference step.
+func awayFromZero(t *Tensor) *Tensor {
+	for i, x := range t.Data {
+		if math.Abs(x) < 0.1 {
+			t.Data[i] = math.Copysign(0.1, x) + x
+		}
+	}
+	return t
+}
+
+// weighted reduces out to a scalar with fixed random weights so that every
+// entry of its gradient differs.
+func weighted(out *Tensor, rng *rand.Rand) *Tensor {
+	return Sum(Mul(out, randomTensor(out.Rows, out.Cols, rng)))
+}
+
+func TestGradCheck(t *testing.T) {
+	rng := rand.New(rand.NewSource(0))
+	a := randomTensor(4, 3, rng)
+	b := randomTensor(3, 2, rng)
+	bias := randomTensor(1, 3, rng)
+	x := awayFromZero(randomTensor(4, 3, rng))
+	target := randomTensor(4, 3, rng)
+	targetCol := randomTensor(4, 1, rng)
+	indices := []int{0, 2, 1, 2}
+	net := NewMLP([]int{3, 8, 2}, Tanh, rng)
+
+	// Each loss draws its weights from its own source so that every call
+	// rebuilds the same graph.
+	weights := func(seed int64) *rand.Rand { return rand.New(rand.NewSource(seed)) }
+	cases := []struct {
+		name   string
+		loss   func() *Tensor
+		params []*Tensor
+	}{
+		{"MatMul", func() *Tensor { return weighted(MatMul(a, b), weights(1)) }, []*Tensor{a, b}},
+		{"Add bias", func() *Tensor { return weighted(Add(a, bias), weights(2)) }, []*Tensor{a, bias}},
+		{"Sub", func() *Tensor { return weighted(Sub(a, x), weights(3)) }, []*Tensor{a, x}},
+		{"Mul", func() *Tensor { return weighted(Mul(a, x), weights(4)) }, []*Tensor{a, x}},
+		{"Scale", func() *Tensor { return weighted(Scale(a, -1.5), weights(5)) }, []*Tensor{a}},
+		{"Tanh", func() *Tensor { return weighted(Tanh(a), weights(6)) }, []*Tensor{a}},
+		{"ReLU", func() *Tensor { return weighted(ReLU(x), weights(7)) }, []*Tensor{x}},
+		{"Exp", func() *Tensor { return weighted(Exp(a), weights(8)) }, []*Tensor{a}},
+		{"LogSoftmax", func() *Tensor { return weighted(LogSoftmax(a), weights(9)) }, []*Tensor{a}},
+		{"Gather", func() *Tensor { return weighted(Gather(a, indices), weights(10)) }, []*Tensor{a}},
+		{"Mean", func() *Tensor { return Mean(Mul(a, a)) }, []*Tensor{a}},
+		{"Clip", func() *Tensor { return weighted(Clip(x, -0.5, 0.5), weights(11)) }, []*Tensor{x}},
+		{"Min", func() *Tensor { return weighted(Min(a, target), weights(12)) }, []*Tensor{a, target}},
+		{"Max", func() *Tensor { return weighted(Max(a, target), weights(13)) }, []*Tensor{a, target}},
+		{"MSE", func() *Tensor { return MSE(a, target) }, []*Tensor{a, target}},
+		{"Huber", func() *Tensor { return Huber(Scale(a, 2.0), target, 1.0) }, []*Tensor{a, target}},
+		{"HuberElements", func() *Tensor { return weighted(HuberElements(a, target, 0.5), weights(14)) }, []*Tensor{a, target}},
+		{"MLP", func() *Tensor { return Huber(Gather(net.Forward(a), []int{0, 1, 1, 0}), targetCol, 1.0) }, net.Params()},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := GradCheck(tc.loss, tc.params, 1e-5); err > gradCheckTolerance {
+				t.Errorf("relative error %.2e exceeds %.0e", err, gradCheckTolerance)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package nn
+
+import (
+	"math"
+	"math/rand"
+)
+
+type Module interface {
+	Forward(x *Tensor) *Tensor
+	Params() []*Tensor
+}
+
+// Dense computes x W + b for a batch x with one sample per row.
+type Dense struct {
+	W *Tensor
+	B *Tensor
+}
+
+// NewDense draws W uniformly from the Glorot range and starts b at zero.
+func NewDense(in, out int, rng *rand.Rand) *Dense {
+	limit := math.Sqrt(6.0 / float64(in+out))
+	w := Zeros(in, out)
+	for i := range w.Data {
+		w.Data[i] = (rng.Float64()*2 - 1) * limit
+	}
+	return &Dense{W: w, B: Zeros(1, out)}
+}
+
+func (d *Dense) Forward(x *Tensor) *Tensor {
+	return Add(MatMul(x, d.W), d.B)
+}
+
+func (d *Dense) Params() []*Tensor {
+	return []*Tensor{d.W, d.B}
+}
+
+// Func lifts a parameter free op such as ReLU or Tanh into a Module.
+type Func func(x *Tensor) *Tensor
+
+func (f Func) Forward(x *Tensor) *Tensor {
+	return f(x)
+}
+
+func (f Func) Params() []*Tensor {
+	return nil
+}
+
+type Sequential []Module
+
+func (s Sequential) Forward(x *Tensor) *Tensor {
+	for _, module := range s {
+		x = module.Forward(x)
+	}
+	return x
+}
+
+func (s Sequential) Params() []*Tensor {
+	params := make([]*Tensor, 0)
+	for _, module := range s {
+		params = append(params, module.Params()...)
+	}
+	return params
+}
+
+// NewMLP stacks dense layers of the given sizes with activation between
+// them and a linear output.
+func NewMLP(sizes []int, activation Func, rng *rand.Rand) Sequential {
+	mlp := make(Sequential, 0, 2*len(sizes))
+	for i := 0; i+1 < len(sizes); i++ {
+		mlp = append(mlp, NewDense(sizes[i], sizes[i+1], rng))
+		if i+2 < len(sizes) {
+			mlp = append(mlp, activation)
+		}
+	}
+	return mlp
+}
+
+// CopyParams overwrites the parameter values of dst with those of src,
+// which must have the same architecture.
+func CopyParams(dst, src Module) {
+	dstParams := dst.Params()
+	for i, param := range src.Params() {
+		copy(dstParams[i].Data, param.Data)
+	}
+}
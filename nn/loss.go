@@ -0,0 +1,42 @@
+package nn
+
+import "math"
+
+func MSE(prediction, target *Tensor) *Tensor {
+	diff := Sub(prediction, target)
+	return Mean(Mul(diff, diff))
+}
+
+// HuberElements is quadratic for errors within delta and linear beyond,
+// kept per element so that callers can weight samples before reducing.
+func HuberElements(prediction, target *Tensor, delta float64) *Tensor {
+	sameShape(prediction, target)
+	out := newResult(prediction.Rows, prediction.Cols, prediction, target)
+	for i := range prediction.Data {
+		diff := prediction.Data[i] - target.Data[i]
+		if math.Abs(diff) <= delta {
+			out.Data[i] = 0.5 * diff * diff
+		} else {
+			out.Data[i] = delta * (math.Abs(diff) - 0.5*delta)
+		}
+	}
+	out.backward = func() {
+		for i := range prediction.Data {
+			diff := prediction.Data[i] - target.Data[i]
+			g := diff
+			if diff > delta {
+				g = delta
+			} else if diff < -delta {
+				g = -delta
+			}
+			g *= out.Grad[i]
+			prediction.Grad[i] += g
+			target.Grad[i] -= g
+		}
+	}
+	return out
+}
+
+func Huber(prediction, target *Tensor, delta float64) *Tensor {
+	return Mean(HuberElements(prediction, target, delta))
+}
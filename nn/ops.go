@@ -0,0 +1,280 @@
+package nn
+
+import (
+	"fmt"
+	"math"
+)
+
+func MatMul(a, b *Tensor) *Tensor {
+	if a.Cols != b.Rows {
+		panic(fmt.Sprintf("nn: MatMul of %dx%d and %dx%d", a.Rows, a.Cols, b.Rows, b.Cols))
+	}
+	out := newResult(a.Rows, b.Cols, a, b)
+	for i := 0; i < a.Rows; i++ {
+		outRow := out.Row(i)
+		for k, aik := range a.Row(i) {
+			bRow := b.Row(k)
+			for j := range outRow {
+				outRow[j] += aik * bRow[j]
+			}
+		}
+	}
+	out.backward = func() {
+		for i := 0; i < a.Rows; i++ {
+			gRow := out.Grad[i*out.Cols : (i+1)*out.Cols]
+			aRow := a.Row(i)
+			aGradRow := a.Grad[i*a.Cols : (i+1)*a.Cols]
+			for k := range aRow {
+				bRow := b.Row(k)
+				bGradRow := b.Grad[k*b.Cols : (k+1)*b.Cols]
+				sum := 0.0
+				for j, g := range gRow {
+					sum += g * bRow[j]
+					bGradRow[j] += g * aRow[k]
+				}
+				aGradRow[k] += sum
+			}
+		}
+	}
+	return out
+}
+
+// Add sums two tensors of the same shape, or adds a 1xCols row such as a
+// bias to every row of a.
+func Add(a, b *Tensor) *Tensor {
+	if b.Rows == 1 && a.Rows != 1 && a.Cols == b.Cols {
+		out := newResult(a.Rows, a.Cols, a, b)
+		for i := range out.Data {
+			out.Data[i] = a.Data[i] + b.Data[i%b.Cols]
+		}
+		out.backward = func() {
+			for i, g := range out.Grad {
+				a.Grad[i] += g
+				b.Grad[i%b.Cols] += g
+			}
+		}
+		return out
+	}
+	sameShape(a, b)
+	out := newResult(a.Rows, a.Cols, a, b)
+	for i := range out.Data {
+		out.Data[i] = a.Data[i] + b.Data[i]
+	}
+	out.backward = func() {
+		for i, g := range out.Grad {
+			a.Grad[i] += g
+			b.Grad[i] += g
+		}
+	}
+	return out
+}
+
+func Sub(a, b *Tensor) *Tensor {
+	sameShape(a, b)
+	out := newResult(a.Rows, a.Cols, a, b)
+	for i := range out.Data {
+		out.Data[i] = a.Data[i] - b.Data[i]
+	}
+	out.backward = func() {
+		for i, g := range out.Grad {
+			a.Grad[i] += g
+			b.Grad[i] -= g
+		}
+	}
+	return out
+}
+
+// Mul is the elementwise product.
+func Mul(a, b *Tensor) *Tensor {
+	sameShape(a, b)
+	out := newResult(a.Rows, a.Cols, a, b)
+	for i := range out.Data {
+		out.Data[i] = a.Data[i] * b.Data[i]
+	}
+	out.backward = func() {
+		for i, g := range out.Grad {
+			a.Grad[i] += g * b.Data[i]
+			b.Grad[i] += g * a.Data[i]
+		}
+	}
+	return out
+}
+
+func Scale(a *Tensor, s float64) *Tensor {
+	out := newResult(a.Rows, a.Cols, a)
+	for i := range out.Data {
+		out.Data[i] = a.Data[i] * s
+	}
+	out.backward = func() {
+		for i, g := range out.Grad {
+			a.Grad[i] += g * s
+		}
+	}
+	return out
+}
+
+func ReLU(a *Tensor) *Tensor {
+	out := newResult(a.Rows, a.Cols, a)
+	for i, x := range a.Data {
+		if x > 0 {
+			out.Data[i] = x
+		}
+	}
+	out.backward = func() {
+		for i, g := range out.Grad {
+			if a.Data[i] > 0 {
+				a.Grad[i] += g
+			}
+		}
+	}
+	return out
+}
+
+func Tanh(a *Tensor) *Tensor {
+	out := newResult(a.Rows, a.Cols, a)
+	for i, x := range a.Data {
+		out.Data[i] = math.Tanh(x)
+	}
+	out.backward = func() {
+		for i, g := range out.Grad {
+			a.Grad[i] += g * (1 - out.Data[i]*out.Data[i])
+		}
+	}
+	return out
+}
+
+func Sum(a *Tensor) *Tensor {
+	out := newResult(1, 1, a)
+	for _, x := range a.Data {
+		out.Data[0] += x
+	}
+	out.backward = func() {
+		for i := range a.Grad {
+			a.Grad[i] += out.Grad[0]
+		}
+	}
+	return out
+}
+
+func Mean(a *Tensor) *Tensor {
+	return Scale(Sum(a), 1.0/float64(len(a.Data)))
+}
+
+// Gather picks column indices[i] of row i and returns them as a column.
+func Gather(a *Tensor, indices []int) *Tensor {
+	if len(indices) != a.Rows {
+		panic(fmt.Sprintf("nn: Gather of %d indices from %d rows", len(indices), a.Rows))
+	}
+	out := newResult(a.Rows, 1, a)
+	for i, j := range indices {
+		out.Data[i] = a.Data[i*a.Cols+j]
+	}
+	out.backward = func() {
+		for i, j := range indices {
+			a.Grad[i*a.Cols+j] += out.Grad[i]
+		}
+	}
+	return out
+}
+
+func Exp(a *Tensor) *Tensor {
+	out := newResult(a.Rows, a.Cols, a)
+	for i, x := range a.Data {
+		out.Data[i] = math.Exp(x)
+	}
+	out.backward = func() {
+		for i, g := range out.Grad {
+			a.Grad[i] += g * out.Data[i]
+		}
+	}
+	return out
+}
+
+// LogSoftmax normalizes every row of logits into log probabilities.
+func LogSoftmax(a *Tensor) *Tensor {
+	out := newResult(a.Rows, a.Cols, a)
+	for i := 0; i < a.Rows; i++ {
+		row := a.Row(i)
+		maxLogit := math.Inf(-1)
+		for _, x := range row {
+			maxLogit = math.Max(maxLogit, x)
+		}
+		sum := 0.0
+		for _, x := range row {
+			sum += math.Exp(x - maxLogit)
+		}
+		logSum := maxLogit + math.Log(sum)
+		outRow := out.Row(i)
+		for j, x := range row {
+			outRow[j] = x - logSum
+		}
+	}
+	out.backward = func() {
+		for i := 0; i < a.Rows; i++ {
+			gRow := out.Grad[i*out.Cols : (i+1)*out.Cols]
+			gSum := 0.0
+			for _, g := range gRow {
+				gSum += g
+			}
+			for j, logProb := range out.Row(i) {
+				a.Grad[i*a.Cols+j] += gRow[j] - math.Exp(logProb)*gSum
+			}
+		}
+	}
+	return out
+}
+
+// Clip bounds every element to [low, high]; the gradient is zero wherever
+// the bound is active.
+func Clip(a *Tensor, low, high float64) *Tensor {
+	out := newResult(a.Rows, a.Cols, a)
+	for i, x := range a.Data {
+		out.Data[i] = math.Max(low, math.Min(high, x))
+	}
+	out.backward = func() {
+		for i, g := range out.Grad {
+			if a.Data[i] >= low && a.Data[i] <= high {
+				a.Grad[i] += g
+			}
+		}
+	}
+	return out
+}
+
+// Min is the elementwise minimum; ties send the gradient to a.
+func Min(a, b *Tensor) *Tensor {
+	sameShape(a, b)
+	out := newResult(a.Rows, a.Cols, a, b)
+	for i := range out.Data {
+		out.Data[i] = math.Min(a.Data[i], b.Data[i])
+	}
+	out.backward = func() {
+		for i, g := range out.Grad {
+			if a.Data[i] <= b.Data[i] {
+				a.Grad[i] += g
+			} else {
+				b.Grad[i] += g
+			}
+		}
+	}
+	return out
+}
+
+// Max is the elementwise maximum; ties send the gradient to a.
+func Max(a, b *Tensor) *Tensor {
+	sameShape(a, b)
+	out := newResult(a.Rows, a.Cols, a, b)
+	for i := range out.Data {
+		out.Data[i] = math.Max(a.Data[i], b.Data[i])
+	}
+	out.backward = func() {
+		for i, g := range out.Grad {
+			if a.Data[i] >= b.Data[i] {
+				a.Grad[i] += g
+			} else {
+				b.Grad[i] += g
+			}
+		}
+	}
+	return out
+}
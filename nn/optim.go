@@ -0,0 +1,102 @@
+package nn
+
+import "math"
+
+type Optimizer interface {
+	Step()
+	ZeroGrad()
+}
+
+func zeroGrad(params []*Tensor) {
+	for _, param := range params {
+		param.ZeroGrad()
+	}
+}
+
+// ClipGradNorm rescales the gradients of params so that their joint L2
+// norm is at most maxNorm, and returns the norm before clipping.
+func ClipGradNorm(params []*Tensor, maxNorm float64) float64 {
+	sum := 0.0
+	for _, param := range params {
+		for _, g := range param.Grad {
+			sum += g * g
+		}
+	}
+	norm := math.Sqrt(sum)
+	if norm > maxNorm {
+		for _, param := range params {
+			for i := range param.Grad {
+				param.Grad[i] *= maxNorm / norm
+			}
+		}
+	}
+	return norm
+}
+
+type SGD struct {
+	params   []*Tensor
+	lr       float64
+	momentum float64
+	velocity [][]float64
+}
+
+func NewSGD(params []*Tensor, lr float64, momentum float64) *SGD {
+	velocity := make([][]float64, len(params))
+	for i, param := range params {
+		velocity[i] = make([]float64, len(param.Data))
+	}
+	return &SGD{params: params, lr: lr, momentum: momentum, velocity: velocity}
+}
+
+func (s *SGD) Step() {
+	for i, param := range s.params {
+		for j, g := range param.Grad {
+			s.velocity[i][j] = s.momentum*s.velocity[i][j] + g
+			param.Data[j] -= s.lr * s.velocity[i][j]
+		}
+	}
+}
+
+func (s *SGD) ZeroGrad() {
+	zeroGrad(s.params)
+}
+
+type Adam struct {
+	params []*Tensor
+	lr     float64
+	beta1  float64
+	beta2  float64
+	eps    float64
+	m      [][]float64
+	v      [][]float64
+	t      int
+}
+
+func NewAdam(params []*Tensor, lr float64) *Adam {
+	m := make([][]float64, len(params))
+	v := make([][]float64, len(params))
+	for i, param := range params {
+		m[i] = make([]float64, len(param.Data))
+		v[i] = make([]float64, len(param.Data))
+	}
+	return &Adam{params: params, lr: lr, beta1: 0.9, beta2: 0.999, eps: 1e-8, m: m, v: v}
+}
+
+func (a *Adam) Step() {
+	a.t++
+	correction1 := 1 - math.Pow(a.beta1, float64(a.t))
+	correction2 := 1 - math.Pow(a.beta2, float64(a.t))
+	for i, param := range a.params {
+		for j, g := range param.Grad {
+			a.m[i][j] = a.beta1*a.m[i][j] + (1-a.beta1)*g
+			a.v[i][j] = a.beta2*a.v[i][j] + (1-a.beta2)*g*g
+			mHat := a.m[i][j] / correction1
+			vHat := a.v[i][j] / correction2
+			param.Data[j] -= a.lr * mHat / (math.Sqrt(vHat) + a.eps)
+		}
+	}
+}
+
+func (a *Adam) ZeroGrad() {
+	zeroGrad(a.params)
+}
@@ -0,0 +1,101 @@
+package nn
+
+import "fmt"
+
+// Tensor is a row-major matrix that remembers how it was computed so that
+// Backward can propagate gradients to the tensors it depends on.
+type Tensor struct {
+	Rows     int
+	Cols     int
+	Data     []float64
+	Grad     []float64
+	parents  []*Tensor
+	backward func()
+}
+
+func New(rows, cols int, data []float64) *Tensor {
+	if len(data) != rows*cols {
+		panic(fmt.Sprintf("nn: %d values for a %dx%d tensor", len(data), rows, cols))
+	}
+	return &Tensor{Rows: rows, Cols: cols, Data: data, Grad: make([]float64, rows*cols)}
+}
+
+func Zeros(rows, cols int) *Tensor {
+	return New(rows, cols, make([]float64, rows*cols))
+}
+
+// FromRows stacks equally long vectors as the rows of a tensor.
+func FromRows(rows [][]float64) *Tensor {
+	cols := len(rows[0])
+	data := make([]float64, 0, len(rows)*cols)
+	for _, row := range rows {
+		data = append(data, row...)
+	}
+	return New(len(rows), cols, data)
+}
+
+func (t *Tensor) At(i, j int) float64 {
+	return t.Data[i*t.Cols+j]
+}
+
+func (t *Tensor) Row(i int) []float64 {
+	return t.Data[i*t.Cols : (i+1)*t.Cols]
+}
+
+func (t *Tensor) Item() float64 {
+	if len(t.Data) != 1 {
+		panic(fmt.Sprintf("nn: Item of a %dx%d tensor", t.Rows, t.Cols))
+	}
+	return t.Data[0]
+}
+
+func (t *Tensor) ZeroGrad() {
+	for i := range t.Grad {
+		t.Grad[i] = 0.0
+	}
+}
+
+// Detach returns a copy of the values that does not take part in Backward.
+func (t *Tensor) Detach() *Tensor {
+	return New(t.Rows, t.Cols, append([]float64{}, t.Data...))
+}
+
+// Backward sets the gradient of the scalar t to 1 and accumulates the
+// gradient of t into every tensor it was computed from.
+func (t *Tensor) Backward() {
+	if len(t.Data) != 1 {
+		panic(fmt.Sprintf("nn: Backward of a %dx%d tensor", t.Rows, t.Cols))
+	}
+	order := make([]*Tensor, 0)
+	visited := make(map[*Tensor]bool)
+	var visit func(node *Tensor)
+	visit = func(node *Tensor) {
+		if visited[node] {
+			return
+		}
+		visited[node] = true
+		for _, parent := range node.parents {
+			visit(parent)
+		}
+		order = append(order, node)
+	}
+	visit(t)
+	t.Grad[0] = 1.0
+	for i := len(order) - 1; i >= 0; i-- {
+		if order[i].backward != nil {
+			order[i].backward()
+		}
+	}
+}
+
+func newResult(rows, cols int, parents ...*Tensor) *Tensor {
+	out := Zeros(rows, cols)
+	out.parents = parents
+	return out
+}
+
+func sameShape(a, b *Tensor) {
+	if a.Rows != b.Rows || a.Cols != b.Cols {
+		panic(fmt.Sprintf("nn: shape mismatch %dx%d and %dx%d", a.Rows, a.Cols, b.Rows, b.Cols))
+	}
+}
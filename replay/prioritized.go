@@ -0,0 +1,173 @@
+package replay
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"sort"
+
+	"reinforcement-learning-playground/schedule"
+)
+
+const priorityEpsilon = 1e-6
+
+// Proportional samples slot i with probability p_i^alpha / sum_k p_k^alpha
+// where p_i = |delta_i| + epsilon, and corrects the bias with weights
+// (N P(i))^-beta scaled so the largest possible weight is 1. beta is read
+// from its schedule once per Sample call.
+type Proportional struct {
+	ring
+	alpha       float64
+	beta        schedule.Schedule
+	tree        *SumTree
+	maxPriority float64
+	t           int
+	rng         *rand.Rand
+}
+
+func NewProportional(capacity int, alpha float64, beta schedule.Schedule, rng *rand.Rand) *Proportional {
+	return &Proportional{
+		ring:        newRing(capacity),
+		alpha:       alpha,
+		beta:        beta,
+		tree:        NewSumTree(capacity),
+		maxPriority: 1.0,
+		rng:         rng,
+	}
+}
+
+func (p *Proportional) Beta() float64 {
+	return p.beta.Value(p.t)
+}
+
+// Add stores the transition with the largest priority seen so far so that
+// it is replayed at least once soon.
+func (p *Proportional) Add(transition Transition) {
+	index := p.add(transition)
+	p.tree.Update(index, p.maxPriority)
+}
+
+// Sample draws one transition from each of n equal slices of the total
+// priority mass.
+func (p *Proportional) Sample(n int) Batch {
+	beta := p.Beta()
+	p.t++
+	batch := Batch{
+		Transitions: make([]Transition, n),
+		Indices:     make([]int, n),
+		Weights:     make([]float64, n),
+	}
+	total := p.tree.Total()
+	size := float64(p.Len())
+	maxWeight := math.Pow(size*p.tree.Min()/total, -beta)
+	segment := total / float64(n)
+	for i := 0; i < n; i++ {
+		index := p.tree.Find((float64(i) + p.rng.Float64()) * segment)
+		batch.Transitions[i] = p.transitions[index]
+		batch.Indices[i] = index
+		batch.Weights[i] = math.Pow(size*p.tree.Get(index)/total, -beta) / maxWeight
+	}
+	return batch
+}
+
+func (p *Proportional) UpdatePriorities(indices []int, tdErrors []float64) {
+	for i, index := range indices {
+		priority := math.Pow(math.Abs(tdErrors[i])+priorityEpsilon, p.alpha)
+		p.tree.Update(index, priority)
+		p.maxPriority = math.Max(p.maxPriority, priority)
+	}
+}
+
+// Rank samples the transition of rank r, ordered by descending |delta|,
+// with probability r^-alpha / sum_k k^-alpha. Like the binary heap of the
+// original paper, the ordering is only refreshed every sortEvery samples,
+// or on every sample when sortEvery is 0, and new transitions join at rank
+// 1 with the largest error seen so far.
+type Rank struct {
+	ring
+	alpha     float64
+	beta      schedule.Schedule
+	errors    []float64
+	order     []int
+	cum       []float64
+	maxError  float64
+	sortEvery int
+	t         int
+	rng       *rand.Rand
+}
+
+func NewRank(capacity int, alpha float64, beta schedule.Schedule, sortEvery int, rng *rand.Rand) *Rank {
+	cum := make([]float64, capacity+1)
+	for r := 1; r <= capacity; r++ {
+		cum[r] = cum[r-1] + math.Pow(float64(r), -alpha)
+	}
+	return &Rank{
+		ring:      newRing(capacity),
+		alpha:     alpha,
+		beta:      beta,
+		errors:    make([]float64, capacity),
+		order:     make([]int, 0, capacity),
+		cum:       cum,
+		maxError:  1.0,
+		sortEvery: sortEvery,
+		rng:       rng,
+	}
+}
+
+func (r *Rank) Beta() float64 {
+	return r.beta.Value(r.t)
+}
+
+func (r *Rank) Add(transition Transition) {
+	index := r.add(transition)
+	if i := slices.Index(r.order, index); i >= 0 {
+		r.order = slices.Delete(r.order, i, i+1)
+	}
+	r.order = slices.Insert(r.order, 0, index)
+	r.errors[index] = r.maxError
+}
+
+func (r *Rank) resort() {
+	sort.SliceStable(r.order, func(i, j int) bool {
+		return r.errors[r.order[i]] > r.errors[r.order[j]]
+	})
+}
+
+// Sample draws one rank from each of n equal slices of the rank
+// probability mass.
+func (r *Rank) Sample(n int) Batch {
+	if r.sortEvery <= 0 || r.t%r.sortEvery == 0 {
+		r.resort()
+	}
+	beta := r.Beta()
+	r.t++
+	batch := Batch{
+		Transitions: make([]Transition, n),
+		Indices:     make([]int, n),
+		Weights:     make([]float64, n),
+	}
+	size := r.Len()
+	total := r.cum[size]
+	maxWeight := math.Pow(float64(size)*math.Pow(float64(size), -r.alpha)/total, -beta)
+	segment := total / float64(n)
+	for i := 0; i < n; i++ {
+		value := (float64(i) + r.rng.Float64()) * segment
+		rank := sort.SearchFloat64s(r.cum[1:size+1], value) + 1
+		if rank > size {
+			rank = size
+		}
+		index := r.order[rank-1]
+		prob := math.Pow(float64(rank), -r.alpha) / total
+		batch.Transitions[i] = r.transitions[index]
+		batch.Indices[i] = index
+		batch.Weights[i] = math.Pow(float64(size)*prob, -beta) / maxWeight
+	}
+	return batch
+}
+
+func (r *Rank) UpdatePriorities(indices []int, tdErrors []float64) {
+	for i, index := range indices {
+		r.errors[index] = math.Abs(tdErrors[i])
+		r.maxError = math.Max(r.maxError, r.errors[index])
+	}
+}
@@ -0,0 +1,116 @@
+package replay
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"reinforcement-learning-playground/schedule"
+)
+
+func transition(reward float64) Transition {
+	return Transition{State: []float64{reward}, Reward: reward}
+}
+
+func TestProportionalFrequenciesFollowPriorities(t *testing.T) {
+	alpha := 0.6
+	p := NewProportional(4, alpha, schedule.Constant(0.4), rand.New(rand.NewSource(0)))
+	tdErrors := []float64{1, 2, 3, 4}
+	for i := range tdErrors {
+		p.Add(transition(float64(i)))
+	}
+	p.UpdatePriorities([]int{0, 1, 2, 3}, tdErrors)
+
+	total := 0.0
+	for _, delta := range tdErrors {
+		total += math.Pow(delta+priorityEpsilon, alpha)
+	}
+	draws := 200000
+	counts := make([]int, len(tdErrors))
+	for i := 0; i < draws; i++ {
+		counts[p.Sample(1).Indices[0]]++
+	}
+	for i, delta := range tdErrors {
+		want := math.Pow(delta+priorityEpsilon, alpha) / total
+		if got := float64(counts[i]) / float64(draws); math.Abs(got-want) > 0.005 {
+			t.Errorf("slot %d sampled with frequency %.4f, want %.4f", i, got, want)
+		}
+	}
+}
+
+func TestProportionalWeights(t *testing.T) {
+	alpha, beta := 0.6, 0.5
+	p := NewProportional(4, alpha, schedule.Constant(beta), rand.New(rand.NewSource(0)))
+	tdErrors := []float64{0.5, 1, 2, 4}
+	for i := range tdErrors {
+		p.Add(transition(float64(i)))
+	}
+	p.UpdatePriorities([]int{0, 1, 2, 3}, tdErrors)
+
+	probs := make([]float64, len(tdErrors))
+	total := 0.0
+	for i, delta := range tdErrors {
+		probs[i] = math.Pow(delta+priorityEpsilon, alpha)
+		total += probs[i]
+	}
+	maxWeight := 0.0
+	for i := range probs {
+		probs[i] /= total
+		maxWeight = math.Max(maxWeight, math.Pow(4*probs[i], -beta))
+	}
+	batch := p.Sample(64)
+	for i, index := range batch.Indices {
+		want := math.Pow(4*probs[index], -beta) / maxWeight
+		if math.Abs(batch.Weights[i]-want) > 1e-12 {
+			t.Errorf("weight of slot %d = %v, want %v", index, batch.Weights[i], want)
+		}
+	}
+}
+
+func TestRankWeights(t *testing.T) {
+	alpha, beta := 0.7, 0.5
+	r := NewRank(4, alpha, schedule.Constant(beta), 0, rand.New(rand.NewSource(0)))
+	for i := 0; i < 4; i++ {
+		r.Add(transition(float64(i)))
+	}
+	r.UpdatePriorities([]int{0, 1, 2, 3}, []float64{3, 1, 4, 2})
+	rankOf := map[int]int{2: 1, 0: 2, 3: 3, 1: 4}
+
+	total := 0.0
+	for rank := 1; rank <= 4; rank++ {
+		total += math.Pow(float64(rank), -alpha)
+	}
+	maxWeight := math.Pow(4*math.Pow(4, -alpha)/total, -beta)
+	batch := r.Sample(64)
+	for i, index := range batch.Indices {
+		prob := math.Pow(float64(rankOf[index]), -alpha) / total
+		want := math.Pow(4*prob, -beta) / maxWeight
+		if math.Abs(batch.Weights[i]-want) > 1e-12 {
+			t.Errorf("weight of slot %d = %v, want %v", index, batch.Weights[i], want)
+		}
+	}
+}
+
+func TestRankPutsNewTransitionsFirst(t *testing.T) {
+	r := NewRank(4, 0.7, schedule.Constant(0.5), 1000, rand.New(rand.NewSource(0)))
+	for i := 0; i < 3; i++ {
+		r.Add(transition(float64(i)))
+	}
+	r.UpdatePriorities([]int{0, 1, 2}, []float64{5, 1, 3})
+	r.resort()
+
+	// Neither the new slot 3 nor slot 0, overwritten once the buffer is
+	// full, may wait for the next sort to reach rank 1.
+	for _, slot := range []int{3, 0} {
+		r.Add(transition(float64(slot)))
+		if r.order[0] != slot {
+			t.Fatalf("rank 1 holds slot %d, want the new transition in slot %d", r.order[0], slot)
+		}
+		if len(r.order) != r.Len() {
+			t.Fatalf("order holds %d slots for %d transitions", len(r.order), r.Len())
+		}
+		if r.errors[slot] != r.maxError {
+			t.Fatalf("new transition has error %v, want the largest seen %v", r.errors[slot], r.maxError)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package replay
+
+import "math/rand"
+
+// Transition is one step of experience in the shape the off-policy agents
+// update on, with the state given as an observation vector. IsGoal marks
+// termination only: a step cut off by a time limit is stored with IsGoal
+// false so that its target still bootstraps from NextState.
+type Transition struct {
+	State     []float64
+	Action    int
+	Reward    float64
+	NextState []float64
+	IsGoal    bool
+}
+
+// Batch carries the sampled transitions together with their buffer slots,
+// for UpdatePriorities, and their importance-sampling weights.
+type Batch struct {
+	Transitions []Transition
+	Indices     []int
+	Weights     []float64
+}
+
+type Buffer interface {
+	Add(transition Transition)
+	Len() int
+	Sample(n int) Batch
+	UpdatePriorities(indices []int, tdErrors []float64)
+}
+
+type ring struct {
+	capacity    int
+	transitions []Transition
+	next        int
+}
+
+func newRing(capacity int) ring {
+	return ring{capacity: capacity, transitions: make([]Transition, 0, capacity)}
+}
+
+func (r *ring) add(transition Transition) int {
+	index := r.next
+	if len(r.transitions) < r.capacity {
+		r.transitions = append(r.transitions, transition)
+	} else {
+		r.transitions[index] = transition
+	}
+	r.next = (r.next + 1) % r.capacity
+	return index
+}
+
+func (r *ring) Len() int {
+	return len(r.transitions)
+}
+
+// Uniform is a fixed capacity ring buffer that samples stored transitions
+// uniformly with replacement.
+type Uniform struct {
+	ring
+	rng *rand.Rand
+}
+
+func NewUniform(capacity int, rng *rand.Rand) *Uniform {
+	return &Uniform{ring: newRing(capacity), rng: rng}
+}
+
+func (u *Uniform) Add(transition Transition) {
+	u.add(transition)
+}
+
+func (u *Uniform) Sample(n int) Batch {
+	batch := Batch{
+		Transitions: make([]Transition, n),
+		Indices:     make([]int, n),
+		Weights:     make([]float64, n),
+	}
+	for i := 0; i < n; i++ {
+		index := u.rng.Intn(len(u.transitions))
+		batch.Transitions[i] = u.transitions[index]
+		batch.Indices[i] = index
+		batch.Weights[i] = 1.0
+	}
+	return batch
+}
+
+func (u *Uniform) UpdatePriorities(indices []int, tdErrors []float64) {}
@@ -0,0 +1,60 @@
+package replay
+
+import "math"
+
+// SumTree stores one priority per buffer slot in the leaves of a binary
+// tree whose inner nodes hold the sum and the minimum of their children, so
+// that prefix-sum lookups and updates take O(log n).
+type SumTree struct {
+	leaves int
+	sum    []float64
+	min    []float64
+}
+
+func NewSumTree(capacity int) *SumTree {
+	leaves := 1
+	for leaves < capacity {
+		leaves *= 2
+	}
+	min := make([]float64, 2*leaves)
+	for i := range min {
+		min[i] = math.Inf(1)
+	}
+	return &SumTree{leaves: leaves, sum: make([]float64, 2*leaves), min: min}
+}
+
+func (s *SumTree) Update(index int, priority float64) {
+	node := index + s.leaves
+	s.sum[node] = priority
+	s.min[node] = priority
+	for node /= 2; node >= 1; node /= 2 {
+		s.sum[node] = s.sum[2*node] + s.sum[2*node+1]
+		s.min[node] = math.Min(s.min[2*node], s.min[2*node+1])
+	}
+}
+
+func (s *SumTree) Get(index int) float64 {
+	return s.sum[index+s.leaves]
+}
+
+func (s *SumTree) Total() float64 {
+	return s.sum[1]
+}
+
+func (s *SumTree) Min() float64 {
+	return s.min[1]
+}
+
+// Find returns the slot whose cumulative priority range contains value.
+func (s *SumTree) Find(value float64) int {
+	node := 1
+	for node < s.leaves {
+		if value < s.sum[2*node] || s.sum[2*node+1] == 0 {
+			node = 2 * node
+		} else {
+			value -= s.sum[2*node]
+			node = 2*node + 1
+		}
+	}
+	return node - s.leaves
+}
@@ -0,0 +1,43 @@
+package replay
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSumTreeTotalsAndFind(t *testing.T) {
+	tree := NewSumTree(5)
+	priorities := []float64{1, 2, 3, 4, 5}
+	for i, p := range priorities {
+		tree.Update(i, p)
+	}
+	tree.Update(1, 0.5)
+	tree.Update(3, 6)
+	priorities[1] = 0.5
+	priorities[3] = 6
+
+	total := 0.0
+	for i, p := range priorities {
+		if got := tree.Get(i); got != p {
+			t.Errorf("Get(%d) = %v, want %v", i, got, p)
+		}
+		total += p
+	}
+	if got := tree.Total(); got != total {
+		t.Errorf("Total() = %v, want %v", got, total)
+	}
+	if got := tree.Min(); got != 0.5 {
+		t.Errorf("Min() = %v, want 0.5", got)
+	}
+
+	// Each slot owns the half-open range [prefix, prefix+p) of the total.
+	prefix := 0.0
+	for i, p := range priorities {
+		for _, value := range []float64{prefix, prefix + p/2, math.Nextafter(prefix+p, 0)} {
+			if got := tree.Find(value); got != i {
+				t.Errorf("Find(%v) = %d, want %d", value, got, i)
+			}
+		}
+		prefix += p
+	}
+}
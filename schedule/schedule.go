@@ -0,0 +1,46 @@
+package schedule
+
+import "math"
+
+// Schedule gives a hyperparameter such as epsilon, a temperature or the
+// importance-sampling exponent as a function of the step t.
+type Schedule interface {
+	Value(t int) float64
+}
+
+type Constant float64
+
+func (c Constant) Value(t int) float64 {
+	return float64(c)
+}
+
+type Linear struct {
+	Start float64
+	End   float64
+	Steps int
+}
+
+func NewLinear(start, end float64, steps int) Linear {
+	return Linear{Start: start, End: end, Steps: steps}
+}
+
+func (l Linear) Value(t int) float64 {
+	if t >= l.Steps {
+		return l.End
+	}
+	return l.Start + (l.End-l.Start)*float64(t)/float64(l.Steps)
+}
+
+type Exponential struct {
+	Start float64
+	End   float64
+	Decay float64
+}
+
+func NewExponential(start, end, decay float64) Exponential {
+	return Exponential{Start: start, End: end, Decay: decay}
+}
+
+func (e Exponential) Value(t int) float64 {
+	return e.End + (e.Start-e.End)*math.Pow(e.Decay, float64(t))
+}
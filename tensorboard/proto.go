@@ -0,0 +1,81 @@
+package tensorboard
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// The few protobuf messages of an event file are encoded by hand. Field
+// numbers follow tensorflow/core/util/event.proto, summary.proto and
+// histogram.proto.
+
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+type message []byte
+
+func (m message) key(field int, wire int) message {
+	return binary.AppendUvarint(m, uint64(field<<3|wire))
+}
+
+func (m message) varint(field int, x uint64) message {
+	return binary.AppendUvarint(m.key(field, wireVarint), x)
+}
+
+func (m message) double(field int, x float64) message {
+	return binary.LittleEndian.AppendUint64(m.key(field, wireFixed64), math.Float64bits(x))
+}
+
+func (m message) float(field int, x float32) message {
+	return binary.LittleEndian.AppendUint32(m.key(field, wireFixed32), math.Float32bits(x))
+}
+
+func (m message) bytes(field int, b []byte) message {
+	m = binary.AppendUvarint(m.key(field, wireBytes), uint64(len(b)))
+	return append(m, b...)
+}
+
+func (m message) packedDoubles(field int, xs []float64) message {
+	packed := make([]byte, 0, 8*len(xs))
+	for _, x := range xs {
+		packed = binary.LittleEndian.AppendUint64(packed, math.Float64bits(x))
+	}
+	return m.bytes(field, packed)
+}
+
+// event encodes an Event with either a file version or a summary.
+func event(wallTime float64, step int64, fileVersion string, summary message) message {
+	m := message{}.double(1, wallTime).varint(2, uint64(step))
+	if fileVersion != "" {
+		m = m.bytes(3, []byte(fileVersion))
+	}
+	if summary != nil {
+		m = m.bytes(5, summary)
+	}
+	return m
+}
+
+// summary encodes a Summary holding one Value.
+func summary(value message) message {
+	return message{}.bytes(1, value)
+}
+
+func scalarValue(tag string, x float32) message {
+	return message{}.bytes(1, []byte(tag)).float(2, x)
+}
+
+func histogramValue(tag string, h Histogram) message {
+	histo := message{}.
+		double(1, h.Min).
+		double(2, h.Max).
+		double(3, h.Num).
+		double(4, h.Sum).
+		double(5, h.SumSquares).
+		packedDoubles(6, h.BucketLimits).
+		packedDoubles(7, h.Buckets)
+	return message{}.bytes(1, []byte(tag)).bytes(5, histo)
+}
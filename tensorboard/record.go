@@ -0,0 +1,32 @@
+package tensorboard
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+)
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// maskedCRC is the CRC32C checksum rotated and offset the way TFRecord
+// stores it.
+func maskedCRC(data []byte) uint32 {
+	crc := crc32.Checksum(data, castagnoli)
+	return (crc>>15 | crc<<17) + 0xa282ead8
+}
+
+// writeRecord frames data as a TFRecord: the little-endian length and its
+// masked CRC, the data, then the masked CRC of the data.
+func writeRecord(w io.Writer, data []byte) error {
+	header := make([]byte, 12)
+	binary.LittleEndian.PutUint64(header, uint64(len(data)))
+	binary.LittleEndian.PutUint32(header[8:], maskedCRC(header[:8]))
+	footer := make([]byte, 4)
+	binary.LittleEndian.PutUint32(footer, maskedCRC(data))
+	for _, b := range [][]byte{header, data, footer} {
+		if _, err := w.Write(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
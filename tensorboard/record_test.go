@@ -0,0 +1,38 @@
+package tensorboard
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteRecordGoldenBytes(t *testing.T) {
+	// The data is the CRC-32C check string, whose checksum is 0xe3069283.
+	// Masked, the checksums of the length and the data are 0x3971f937 and
+	// 0xc78ab0e5.
+	want := []byte{
+		0x09, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x37, 0xf9, 0x71, 0x39,
+		'1', '2', '3', '4', '5', '6', '7', '8', '9',
+		0xe5, 0xb0, 0x8a, 0xc7,
+	}
+	var b bytes.Buffer
+	if err := writeRecord(&b, []byte("123456789")); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(b.Bytes(), want) {
+		t.Fatalf("record = % x, want % x", b.Bytes(), want)
+	}
+}
+
+func TestWriteRecordMatchesEventFileHeader(t *testing.T) {
+	// Event files written by TensorFlow start with the 24-byte file version
+	// event, framed by this length and masked checksum.
+	want := []byte{0x18, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xa3, 0x7f, 0x4b, 0x22}
+	var b bytes.Buffer
+	if err := writeRecord(&b, make([]byte, 24)); err != nil {
+		t.Fatal(err)
+	}
+	if got := b.Bytes()[:12]; !bytes.Equal(got, want) {
+		t.Fatalf("header = % x, want % x", got, want)
+	}
+}
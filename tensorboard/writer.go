@@ -0,0 +1,116 @@
+package tensorboard
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Writer appends scalar and histogram summaries to an event file that
+// TensorBoard reads when pointed at its directory.
+type Writer struct {
+	file   *os.File
+	buffer *bufio.Writer
+}
+
+// NewWriter creates dir and a new events.out.tfevents file inside it.
+func NewWriter(dir string) (*Writer, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	host, err := os.Hostname()
+	if err != nil {
+		host = "localhost"
+	}
+	now := time.Now()
+	name := fmt.Sprintf("events.out.tfevents.%d.%s", now.Unix(), host)
+	file, err := os.Create(filepath.Join(dir, name))
+	if err != nil {
+		return nil, err
+	}
+	w := &Writer{file: file, buffer: bufio.NewWriter(file)}
+	if err := w.write(event(wallTime(now), 0, "brain.Event:2", nil)); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return w, nil
+}
+
+func wallTime(t time.Time) float64 {
+	return float64(t.UnixNano()) / 1e9
+}
+
+func (w *Writer) write(m message) error {
+	return writeRecord(w.buffer, m)
+}
+
+func (w *Writer) Scalar(tag string, step int64, value float64) error {
+	return w.write(event(wallTime(time.Now()), step, "", summary(scalarValue(tag, float32(value)))))
+}
+
+func (w *Writer) Histogram(tag string, step int64, values []float64, buckets int) error {
+	h := NewHistogram(values, buckets)
+	return w.write(event(wallTime(time.Now()), step, "", summary(histogramValue(tag, h))))
+}
+
+// Flush makes the events written so far visible to TensorBoard.
+func (w *Writer) Flush() error {
+	return w.buffer.Flush()
+}
+
+func (w *Writer) Close() error {
+	if err := w.Flush(); err != nil {
+		w.file.Close()
+		return err
+	}
+	return w.file.Close()
+}
+
+// Histogram is the HistogramProto of TensorBoard. Buckets[i] counts the
+// values from BucketLimits[i-1] up to but excluding BucketLimits[i]; the
+// last bucket also holds the maximum.
+type Histogram struct {
+	Min          float64
+	Max          float64
+	Num          float64
+	Sum          float64
+	SumSquares   float64
+	BucketLimits []float64
+	Buckets      []float64
+}
+
+// NewHistogram spreads values over equal-width buckets between their
+// minimum and maximum.
+func NewHistogram(values []float64, buckets int) Histogram {
+	h := Histogram{Min: math.Inf(1), Max: math.Inf(-1), Num: float64(len(values))}
+	for _, x := range values {
+		h.Min = math.Min(h.Min, x)
+		h.Max = math.Max(h.Max, x)
+		h.Sum += x
+		h.SumSquares += x * x
+	}
+	if len(values) == 0 {
+		h.Min, h.Max = 0, 0
+	}
+	if h.Max == h.Min || buckets < 1 {
+		buckets = 1
+	}
+	width := (h.Max - h.Min) / float64(buckets)
+	h.BucketLimits = make([]float64, buckets)
+	h.Buckets = make([]float64, buckets)
+	for i := range h.BucketLimits {
+		h.BucketLimits[i] = h.Min + width*float64(i+1)
+	}
+	h.BucketLimits[buckets-1] = h.Max
+	for _, x := range values {
+		i := buckets - 1
+		if width > 0 {
+			i = min(int((x-h.Min)/width), buckets-1)
+		}
+		h.Buckets[i]++
+	}
+	return h
+}
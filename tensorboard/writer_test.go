@@ -0,0 +1,57 @@
+package tensorboard
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewHistogram(t *testing.T) {
+	cases := []struct {
+		name    string
+		values  []float64
+		buckets int
+		want    Histogram
+	}{
+		{"empty", nil, 4, Histogram{
+			BucketLimits: []float64{0},
+			Buckets:      []float64{0},
+		}},
+		{"all values equal", []float64{2, 2, 2}, 4, Histogram{
+			Min: 2, Max: 2, Num: 3, Sum: 6, SumSquares: 12,
+			BucketLimits: []float64{2},
+			Buckets:      []float64{3},
+		}},
+		{"max in the last bucket", []float64{0, 1, 2, 3, 4}, 4, Histogram{
+			Min: 0, Max: 4, Num: 5, Sum: 10, SumSquares: 30,
+			BucketLimits: []float64{1, 2, 3, 4},
+			Buckets:      []float64{1, 1, 1, 2},
+		}},
+		{"no buckets", []float64{-1, 1}, 0, Histogram{
+			Min: -1, Max: 1, Num: 2, Sum: 0, SumSquares: 2,
+			BucketLimits: []float64{1},
+			Buckets:      []float64{2},
+		}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := NewHistogram(c.values, c.buckets); !reflect.DeepEqual(got, c.want) {
+				t.Fatalf("NewHistogram(%v, %d) = %+v, want %+v", c.values, c.buckets, got, c.want)
+			}
+		})
+	}
+}
+
+func TestNewHistogramCountsEveryValue(t *testing.T) {
+	// The maximum is three widths above the minimum, one past the last index.
+	h := NewHistogram([]float64{0.1, 0.3, 0.5, 0.7}, 3)
+	if h.Buckets[len(h.Buckets)-1] < 1 || h.BucketLimits[len(h.BucketLimits)-1] != 0.7 {
+		t.Fatalf("maximum 0.7 not in the last bucket: %+v", h)
+	}
+	total := 0.0
+	for _, count := range h.Buckets {
+		total += count
+	}
+	if total != 4 {
+		t.Fatalf("buckets hold %v values, want 4", total)
+	}
+}
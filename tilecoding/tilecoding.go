@@ -0,0 +1,131 @@
+package tilecoding
+
+import (
+	"hash/fnv"
+	"math"
+)
+
+// IHT assigns consecutive indices to tile coordinates until size indices are
+// in use, after which new coordinates are hashed into the table and may
+// collide.
+type IHT struct {
+	size          int
+	dictionary    map[string]int
+	overfullCount int
+}
+
+func NewIHT(size int) *IHT {
+	return &IHT{size: size, dictionary: make(map[string]int)}
+}
+
+func (h *IHT) Size() int {
+	return h.size
+}
+
+func (h *IHT) Count() int {
+	return len(h.dictionary)
+}
+
+func (h *IHT) OverfullCount() int {
+	return h.overfullCount
+}
+
+func (h *IHT) index(coords []int) int {
+	key := make([]byte, 0, len(coords)*8)
+	for _, c := range coords {
+		for i := 0; i < 8; i++ {
+			key = append(key, byte(uint64(c)>>(8*i)))
+		}
+	}
+	if index, ok := h.dictionary[string(key)]; ok {
+		return index
+	}
+	if len(h.dictionary) >= h.size {
+		h.overfullCount++
+		hasher := fnv.New64a()
+		hasher.Write(key)
+		return int(hasher.Sum64() % uint64(h.size))
+	}
+	index := len(h.dictionary)
+	h.dictionary[string(key)] = index
+	return index
+}
+
+// Tiles returns one active index per tiling for the point floats, which are
+// expected to be scaled so that one unit is the width of a tile. Each tiling
+// is offset asymmetrically as in Sutton's tiles3. ints are appended to the
+// coordinates unchanged, e.g. to give each action its own tiles.
+func Tiles(iht *IHT, numTilings int, floats []float64, ints []int) []int {
+	qfloats := make([]int, len(floats))
+	for i, f := range floats {
+		qfloats[i] = int(math.Floor(f * float64(numTilings)))
+	}
+	tiles := make([]int, numTilings)
+	for tiling := 0; tiling < numTilings; tiling++ {
+		coords := make([]int, 0, 1+len(floats)+len(ints))
+		coords = append(coords, tiling)
+		b := tiling
+		for _, q := range qfloats {
+			coords = append(coords, floorDiv(q+b, numTilings))
+			b += tiling * 2
+		}
+		coords = append(coords, ints...)
+		tiles[tiling] = iht.index(coords)
+	}
+	return tiles
+}
+
+func floorDiv(a, b int) int {
+	q := a / b
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		q--
+	}
+	return q
+}
+
+// TileCoder scales observations within [low, high] so that each dimension is
+// covered by tilesPerDim tiles in every tiling.
+type TileCoder struct {
+	iht        *IHT
+	numTilings int
+	low        []float64
+	scale      []float64
+}
+
+func NewTileCoder(size int, numTilings int, tilesPerDim int, low []float64, high []float64) *TileCoder {
+	scale := make([]float64, len(low))
+	for i := range low {
+		scale[i] = float64(tilesPerDim) / (high[i] - low[i])
+	}
+	return &TileCoder{
+		iht:        NewIHT(size),
+		numTilings: numTilings,
+		low:        low,
+		scale:      scale,
+	}
+}
+
+func (t *TileCoder) Size() int {
+	return t.iht.Size()
+}
+
+func (t *TileCoder) NumTilings() int {
+	return t.numTilings
+}
+
+func (t *TileCoder) Active(observation []float64, action int) []int {
+	floats := make([]float64, len(observation))
+	for i, o := range observation {
+		floats[i] = (o - t.low[i]) * t.scale[i]
+	}
+	return Tiles(t.iht, t.numTilings, floats, []int{action})
+}
+
+// Features expands the active tiles into a dense binary vector.
+func (t *TileCoder) Features(observation []float64, action int) []float64 {
+	features := make([]float64, t.iht.Size())
+	for _, index := range t.Active(observation, action) {
+		features[index] = 1.0
+	}
+	return features
+}
@@ -0,0 +1,79 @@
+package tilecoding
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func shared(a, b []int) int {
+	count := 0
+	for _, x := range a {
+		for _, y := range b {
+			if x == y {
+				count++
+				break
+			}
+		}
+	}
+	return count
+}
+
+func TestTilesDistinctAndInRange(t *testing.T) {
+	for _, size := range []int{4096, 16} {
+		iht := NewIHT(size)
+		rng := rand.New(rand.NewSource(0))
+		for i := 0; i < 200; i++ {
+			point := []float64{rng.Float64() * 10, rng.Float64() * 10}
+			tiles := Tiles(iht, 8, point, []int{rng.Intn(3)})
+			if len(tiles) != 8 {
+				t.Fatalf("size %d: %d tiles, want 8", size, len(tiles))
+			}
+			seen := make(map[int]bool)
+			for _, index := range tiles {
+				if index < 0 || index >= size {
+					t.Fatalf("size %d: index %d out of range", size, index)
+				}
+				seen[index] = true
+			}
+			// Once the table is full, hashed indices may collide.
+			if iht.OverfullCount() == 0 && len(seen) != len(tiles) {
+				t.Fatalf("size %d: repeated index in %v", size, tiles)
+			}
+		}
+	}
+}
+
+// Each tiling is offset by a fraction of a tile, so a small move in one
+// dimension crosses the tile boundary of at most one tiling.
+func TestNearbyPointsShareMostTiles(t *testing.T) {
+	iht := NewIHT(4096)
+	rng := rand.New(rand.NewSource(0))
+	for i := 0; i < 200; i++ {
+		point := []float64{rng.Float64() * 10, rng.Float64() * 10}
+		nearby := []float64{point[0] + 0.01, point[1]}
+		got := shared(Tiles(iht, 8, point, nil), Tiles(iht, 8, nearby, nil))
+		if got < 7 {
+			t.Fatalf("%v and %v share %d tiles, want at least 7", point, nearby, got)
+		}
+	}
+}
+
+func TestDistantPointsShareNoTiles(t *testing.T) {
+	iht := NewIHT(4096)
+	rng := rand.New(rand.NewSource(0))
+	for i := 0; i < 200; i++ {
+		point := []float64{rng.Float64() * 10, rng.Float64() * 10}
+		distant := []float64{point[0], point[1] + 1.01}
+		if got := shared(Tiles(iht, 8, point, nil), Tiles(iht, 8, distant, nil)); got != 0 {
+			t.Fatalf("%v and %v share %d tiles, want none", point, distant, got)
+		}
+	}
+}
+
+func TestActionsGetTheirOwnTiles(t *testing.T) {
+	coder := NewTileCoder(4096, 8, 8, []float64{-1.2, -0.07}, []float64{0.6, 0.07})
+	observation := []float64{-0.5, 0.0}
+	if got := shared(coder.Active(observation, 0), coder.Active(observation, 1)); got != 0 {
+		t.Fatalf("actions 0 and 1 share %d tiles, want none", got)
+	}
+}
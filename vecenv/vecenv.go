@@ -0,0 +1,137 @@
+package vecenv
+
+import (
+	"errors"
+
+	"reinforcement-learning-playground/classiccontrol"
+)
+
+// Result is the outcome of one environment copy after a batched step. When
+// the episode terminated or was truncated, Observation already belongs to
+// the next episode and FinalObservation holds the observation the episode
+// ended on, which a truncated transition should bootstrap from.
+type Result struct {
+	Observation      []float64
+	Reward           float64
+	IsGoal           bool
+	Truncated        bool
+	FinalObservation []float64
+}
+
+type command struct {
+	reset  bool
+	action int
+}
+
+type worker struct {
+	env      classiccontrol.Env
+	commands chan command
+	results  chan Result
+}
+
+func (w *worker) run() {
+	for cmd := range w.commands {
+		if cmd.reset {
+			w.results <- Result{Observation: w.env.Reset()}
+			continue
+		}
+		observation, reward, isGoal, truncated := w.env.Step(cmd.action)
+		result := Result{Observation: observation, Reward: reward, IsGoal: isGoal, Truncated: truncated}
+		if isGoal || truncated {
+			result.FinalObservation = observation
+			result.Observation = w.env.Reset()
+		}
+		w.results <- result
+	}
+}
+
+// VecEnv steps several copies of an environment, each in its own
+// goroutine, with one action and one Result per copy.
+type VecEnv struct {
+	workers []*worker
+	waiting bool
+}
+
+// New builds n copies with newEnv, which receives the copy index so that
+// each copy can own its random source.
+func New(n int, newEnv func(i int) classiccontrol.Env) *VecEnv {
+	workers := make([]*worker, n)
+	for i := range workers {
+		workers[i] = &worker{
+			env:      newEnv(i),
+			commands: make(chan command),
+			results:  make(chan Result, 1),
+		}
+		go workers[i].run()
+	}
+	return &VecEnv{workers: workers}
+}
+
+func (v *VecEnv) Len() int {
+	return len(v.workers)
+}
+
+func (v *VecEnv) Actions() []int {
+	return v.workers[0].env.Actions()
+}
+
+// Reset starts a new episode in every copy. It fails while a step started
+// by StepAsync has not been collected, since the copies would otherwise
+// hand back that step's results in place of the reset observations.
+func (v *VecEnv) Reset() ([][]float64, error) {
+	if v.waiting {
+		return nil, errors.New("previous step has not been collected")
+	}
+	for _, w := range v.workers {
+		w.commands <- command{reset: true}
+	}
+	observations := make([][]float64, len(v.workers))
+	for i, w := range v.workers {
+		observations[i] = (<-w.results).Observation
+	}
+	return observations, nil
+}
+
+// StepAsync hands one action to every copy and returns without waiting, so
+// that the caller can work while the copies step. Collect the results with
+// StepWait.
+func (v *VecEnv) StepAsync(actions []int) error {
+	if len(actions) != len(v.workers) {
+		return errors.New("one action per environment is required")
+	}
+	if v.waiting {
+		return errors.New("previous step has not been collected")
+	}
+	for i, w := range v.workers {
+		w.commands <- command{action: actions[i]}
+	}
+	v.waiting = true
+	return nil
+}
+
+func (v *VecEnv) StepWait() ([]Result, error) {
+	if !v.waiting {
+		return nil, errors.New("no step in progress")
+	}
+	results := make([]Result, len(v.workers))
+	for i, w := range v.workers {
+		results[i] = <-w.results
+	}
+	v.waiting = false
+	return results, nil
+}
+
+// Step is the synchronous form of StepAsync followed by StepWait.
+func (v *VecEnv) Step(actions []int) ([]Result, error) {
+	if err := v.StepAsync(actions); err != nil {
+		return nil, err
+	}
+	return v.StepWait()
+}
+
+// Close stops the goroutines. The VecEnv must not be used afterwards.
+func (v *VecEnv) Close() {
+	for _, w := range v.workers {
+		close(w.commands)
+	}
+}
@@ -0,0 +1,184 @@
+package vecenv
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"reinforcement-learning-playground/classiccontrol"
+)
+
+// counter walks from 0 by action+1 per step and pays its new position. It
+// terminates at goal and is truncated after maxSteps. Its observation is
+// its id followed by the position.
+type counter struct {
+	id       float64
+	position float64
+	steps    int
+	goal     float64
+	maxSteps int
+	barrier  *barrier
+}
+
+func (c *counter) Reset() []float64 {
+	c.position = 0
+	c.steps = 0
+	return []float64{c.id, c.position}
+}
+
+func (c *counter) Step(action int) ([]float64, float64, bool, bool) {
+	if c.barrier != nil {
+		c.barrier.wait()
+	}
+	c.position += float64(action + 1)
+	c.steps++
+	terminated := c.position >= c.goal
+	truncated := !terminated && c.steps >= c.maxSteps
+	return []float64{c.id, c.position}, c.position, terminated, truncated
+}
+
+func (c *counter) Actions() []int  { return []int{0, 1} }
+func (c *counter) Low() []float64  { return []float64{0, 0} }
+func (c *counter) High() []float64 { return []float64{10, 10} }
+
+// barrier blocks every Step until n of them are in progress at once, or
+// gives up after a second and records that the steps ran one at a time.
+type barrier struct {
+	n       int
+	mu      sync.Mutex
+	arrived int
+	release chan struct{}
+	timeout bool
+}
+
+func (b *barrier) wait() {
+	b.mu.Lock()
+	b.arrived++
+	if b.arrived == b.n {
+		close(b.release)
+	}
+	b.mu.Unlock()
+	select {
+	case <-b.release:
+	case <-time.After(time.Second):
+		b.mu.Lock()
+		b.timeout = true
+		b.mu.Unlock()
+	}
+}
+
+func newCounters(n int, goal float64, maxSteps int, b *barrier) *VecEnv {
+	return New(n, func(i int) classiccontrol.Env {
+		return &counter{id: float64(i), goal: goal, maxSteps: maxSteps, barrier: b}
+	})
+}
+
+func TestStepsCopiesInParallel(t *testing.T) {
+	b := &barrier{n: 3, release: make(chan struct{})}
+	v := newCounters(3, 100, 100, b)
+	defer v.Close()
+	if _, err := v.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	results, err := v.Step([]int{0, 1, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.timeout {
+		t.Fatal("copies did not step at the same time")
+	}
+	for i, result := range results {
+		want := float64(i + 1)
+		if result.Observation[0] != float64(i) || result.Observation[1] != want || result.Reward != want {
+			t.Errorf("copy %d: observation %v reward %v, want [%d %v] and %v", i, result.Observation, result.Reward, i, want, want)
+		}
+		if result.IsGoal || result.Truncated || result.FinalObservation != nil {
+			t.Errorf("copy %d: episode ended after one step: %+v", i, result)
+		}
+	}
+}
+
+func TestResetsEndedEpisodes(t *testing.T) {
+	// Copy 0 reaches the goal of 3 in one step of action 2, copy 1 moves by
+	// one and is truncated after its second step.
+	v := newCounters(2, 3, 2, nil)
+	defer v.Close()
+	observations, err := v.Reset()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if observations[1][0] != 1 || observations[1][1] != 0 {
+		t.Fatalf("reset observation of copy 1 = %v, want [1 0]", observations[1])
+	}
+
+	results, err := v.Step([]int{2, 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !results[0].IsGoal || results[0].Truncated {
+		t.Fatalf("copy 0: %+v, want terminated", results[0])
+	}
+	if results[0].FinalObservation[1] != 3 || results[0].Observation[1] != 0 {
+		t.Fatalf("copy 0: final %v, next %v, want position 3 then a reset to 0", results[0].FinalObservation, results[0].Observation)
+	}
+	if results[1].IsGoal || results[1].Truncated || results[1].Observation[1] != 1 {
+		t.Fatalf("copy 1: %+v, want a running episode at position 1", results[1])
+	}
+
+	results, err = v.Step([]int{0, 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results[0].IsGoal || results[0].Observation[1] != 1 {
+		t.Fatalf("copy 0: %+v, want the new episode at position 1", results[0])
+	}
+	if results[1].IsGoal || !results[1].Truncated {
+		t.Fatalf("copy 1: %+v, want truncated", results[1])
+	}
+	if results[1].FinalObservation[1] != 2 || results[1].Observation[1] != 0 {
+		t.Fatalf("copy 1: final %v, next %v, want position 2 then a reset to 0", results[1].FinalObservation, results[1].Observation)
+	}
+}
+
+func TestRejectsCallsOutOfOrder(t *testing.T) {
+	v := newCounters(2, 100, 100, nil)
+	defer v.Close()
+	if _, err := v.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := v.StepWait(); err == nil {
+		t.Error("StepWait without a step in progress succeeded")
+	}
+	if err := v.StepAsync([]int{0}); err == nil {
+		t.Error("StepAsync with one action for two copies succeeded")
+	}
+	if err := v.StepAsync([]int{0, 0}); err != nil {
+		t.Fatal(err)
+	}
+	if err := v.StepAsync([]int{0, 0}); err == nil {
+		t.Error("StepAsync while a step is pending succeeded")
+	}
+	if _, err := v.Reset(); err == nil {
+		t.Error("Reset while a step is pending succeeded")
+	}
+
+	// The pending step is still collected intact after the rejected calls.
+	results, err := v.StepWait()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, result := range results {
+		if result.Observation[1] != 1 {
+			t.Errorf("copy %d: observation %v, want position 1", i, result.Observation)
+		}
+	}
+	observations, err := v.Reset()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, observation := range observations {
+		if observation[1] != 0 {
+			t.Errorf("copy %d: reset observation %v, want position 0", i, observation)
+		}
+	}
+}